@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 
 	"github.com/Harsh-Pratap-Singh/Search_Engine/pagerank"
 )
@@ -33,7 +34,10 @@ func main() {
 	results := calc.Calculate(backlinks, outlinksCount)
 
 	fmt.Printf("Total URLs processed: %d\n\n", len(results))
-	pagerank.Print(results, *limit)
+	if err := pagerank.WriteResultsColored(os.Stdout, results, *limit); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	fmt.Println("\nPageRank convergence demonstration:")
 	for _, n := range []int{1, 5, 10, 50} {
@@ -53,6 +53,11 @@ func main() {
 	addr := flag.String("addr", ":8080", "listen address")
 	flag.Parse()
 
+	defaults, err := pagerank.NewFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	s := &store{scores: map[string]float64{}}
 	seedDemo(s)
 
@@ -92,7 +97,7 @@ func main() {
 			return
 		}
 
-		calc := pagerank.New()
+		calc := defaults.Clone()
 		if req.Damping > 0 {
 			calc.SetDamping(req.Damping)
 		}
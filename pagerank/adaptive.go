@@ -0,0 +1,56 @@
+package pagerank
+
+import "math"
+
+// adaptiveDamping bounds the per-iteration damping set by
+// SetAdaptiveDamping. The centre is the calculator's damping.
+type adaptiveDamping struct {
+	min, max float64
+}
+
+// SetAdaptiveDamping varies the damping from one iteration to the next,
+// starting at initialDamping and kept within [minDamping, maxDamping].
+// After iteration k the variance v of the rank vector is compared with the
+// previous iteration's v', and iteration k+1 uses
+//
+//	d = initialDamping + (maxDamping - minDamping) * (v - v') / (v * k)
+//
+// clamped to the bounds. While the ranks are still spreading out the
+// variance grows and damping rises towards maxDamping; once the variance
+// shrinks, damping drops below initialDamping towards minDamping. The 1/k
+// factor keeps the adjustment from feeding back into oscillation, and as
+// the ranks settle d returns to initialDamping, so the fixed point is the
+// one for initialDamping. Bounds outside 0 < min <= initial <= max < 1 are
+// ignored, and SetDamping afterwards moves the centre. CalculateCSR and
+// CalculateCompact always use the fixed damping.
+func (c *Calculator) SetAdaptiveDamping(initialDamping, minDamping, maxDamping float64) *Calculator {
+	if minDamping > 0 && minDamping <= initialDamping && initialDamping <= maxDamping && maxDamping < 1 {
+		c.damping = initialDamping
+		c.adaptive = &adaptiveDamping{min: minDamping, max: maxDamping}
+	}
+	return c
+}
+
+// next returns the damping for the iteration after iteration k, which
+// moved the rank variance from prev to cur.
+func (a *adaptiveDamping) next(initial, prev, cur float64, k int) float64 {
+	d := initial
+	if cur > 0 {
+		d += (a.max - a.min) * (cur - prev) / (cur * float64(k))
+	}
+	return math.Min(math.Max(d, a.min), a.max)
+}
+
+func variance[F rankFloat](rank []F) float64 {
+	var mean float64
+	for _, r := range rank {
+		mean += float64(r)
+	}
+	mean /= float64(len(rank))
+	var sum float64
+	for _, r := range rank {
+		d := float64(r) - mean
+		sum += d * d
+	}
+	return sum / float64(len(rank))
+}
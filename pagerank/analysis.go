@@ -0,0 +1,180 @@
+package pagerank
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Sources whose outlink counts vary by less than this fraction of their
+// mean look bought rather than earned, given enough of them.
+const (
+	uniformStdDevRatio = 0.05
+	uniformMinSources  = 5
+)
+
+// SimulateNodeRemoval ranks the graph as given and again with url and all
+// of its links removed. Pages that linked to url have their outlink count
+// reduced to match.
+func SimulateNodeRemoval(url string, backlinks map[string][]string, outlinksCount map[string]int, calc *Calculator) (before, after []Result) {
+	before = calc.Calculate(backlinks, outlinksCount)
+	prunedBacklinks, prunedCounts := withoutNode(url, backlinks, outlinksCount)
+	after = calc.Calculate(prunedBacklinks, prunedCounts)
+	return before, after
+}
+
+// withoutNode returns copies of the graph maps with url removed.
+func withoutNode(url string, backlinks map[string][]string, outlinksCount map[string]int) (map[string][]string, map[string]int) {
+	counts := make(map[string]int, len(outlinksCount))
+	for u, n := range outlinksCount {
+		if u != url {
+			counts[u] = n
+		}
+	}
+	for _, src := range backlinks[url] {
+		if _, ok := counts[src]; ok && counts[src] > 0 {
+			counts[src]--
+		}
+	}
+
+	pruned := make(map[string][]string, len(backlinks))
+	for target, sources := range backlinks {
+		if target == url {
+			continue
+		}
+		kept := make([]string, 0, len(sources))
+		for _, src := range sources {
+			if src != url {
+				kept = append(kept, src)
+			}
+		}
+		pruned[target] = kept
+	}
+	return pruned, counts
+}
+
+type LinkDependency struct {
+	Source   string
+	Target   string
+	RankLoss float64
+}
+
+// FindCriticalDependencies estimates, for every link, how much rank Target
+// would lose if Source went away: Source's rank divided by its outlink
+// count. Links whose estimated loss exceeds threshold are returned,
+// largest loss first. The estimate does not depend on calc.
+func FindCriticalDependencies(results []Result, backlinks map[string][]string, outlinksCount map[string]int, calc *Calculator, threshold float64) []LinkDependency {
+	rank := make(map[string]float64, len(results))
+	for _, r := range results {
+		rank[r.URL] = r.Rank
+	}
+
+	var deps []LinkDependency
+	for target, sources := range backlinks {
+		for _, src := range sources {
+			out := outlinksCount[src]
+			if out <= 0 {
+				out = 1
+			}
+			loss := rank[src] / float64(out)
+			if loss > threshold {
+				deps = append(deps, LinkDependency{Source: src, Target: target, RankLoss: loss})
+			}
+		}
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].RankLoss != deps[j].RankLoss {
+			return deps[i].RankLoss > deps[j].RankLoss
+		}
+		if deps[i].Source != deps[j].Source {
+			return deps[i].Source < deps[j].Source
+		}
+		return deps[i].Target < deps[j].Target
+	})
+	return deps
+}
+
+// Explain breaks url's rank down into what each backlink passes along
+// (the damped share of its own rank) and the teleportation term, largest
+// inlink contribution first. With converged ranks the parts add up to the
+// page's rank.
+func (c *Calculator) Explain(url string, backlinks map[string][]string, outlinksCount map[string]int, ranks map[string]float64) string {
+	type part struct {
+		src     string
+		out     int
+		contrib float64
+	}
+	parts := make([]part, 0, len(backlinks[url]))
+	for _, src := range backlinks[url] {
+		out := outlinksCount[src]
+		if out <= 0 {
+			out = 1
+		}
+		parts = append(parts, part{src, out, c.damping * ranks[src] / float64(out)})
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		if parts[i].contrib != parts[j].contrib {
+			return parts[i].contrib > parts[j].contrib
+		}
+		return parts[i].src < parts[j].src
+	})
+
+	urls := collectURLs(backlinks, outlinksCount)
+	var teleport float64
+	if i := sort.SearchStrings(urls, url); i < len(urls) && urls[i] == url {
+		teleport = c.teleportVector(urls)[i]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Page %s has rank %.4g because: [", url, ranks[url])
+	for _, p := range parts {
+		fmt.Fprintf(&b, "%s (rank=%.4g, outlinks=%d) contributes %.4g, ", p.src, ranks[p.src], p.out, p.contrib)
+	}
+	fmt.Fprintf(&b, "teleportation contributes %.4g]", teleport)
+	return b.String()
+}
+
+// InlinkAnalysis summarizes the outlink counts of the pages linking to a
+// page.
+type InlinkAnalysis struct {
+	SourceCount         int
+	OutlinkCountMean    float64
+	OutlinkCountStdDev  float64
+	OutlinkCountMedian  float64
+	SuspiciouslyUniform bool
+}
+
+// AnalyzeInlinkSources describes the distinct pages linking to url by
+// their outlink counts. Paid links tend to come from pages that all carry
+// the same number of links, so the set is flagged SuspiciouslyUniform when
+// there are at least 5 sources and the population standard deviation is
+// under 5% of the mean.
+func AnalyzeInlinkSources(url string, backlinks map[string][]string, outlinksCount map[string]int) InlinkAnalysis {
+	sources := dedupeSorted(backlinks[url])
+	a := InlinkAnalysis{SourceCount: len(sources)}
+	if len(sources) == 0 {
+		return a
+	}
+	counts := make([]float64, len(sources))
+	for i, src := range sources {
+		counts[i] = float64(outlinksCount[src])
+		a.OutlinkCountMean += counts[i]
+	}
+	n := float64(len(counts))
+	a.OutlinkCountMean /= n
+	var sq float64
+	for _, c := range counts {
+		sq += (c - a.OutlinkCountMean) * (c - a.OutlinkCountMean)
+	}
+	a.OutlinkCountStdDev = math.Sqrt(sq / n)
+	sort.Float64s(counts)
+	if mid := len(counts) / 2; len(counts)%2 == 1 {
+		a.OutlinkCountMedian = counts[mid]
+	} else {
+		a.OutlinkCountMedian = (counts[mid-1] + counts[mid]) / 2
+	}
+	a.SuspiciouslyUniform = len(counts) >= uniformMinSources && a.OutlinkCountMean > 0 &&
+		a.OutlinkCountStdDev < uniformStdDevRatio*a.OutlinkCountMean
+	return a
+}
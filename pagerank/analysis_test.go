@@ -0,0 +1,151 @@
+package pagerank
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func hubGraph() (map[string][]string, map[string]int) {
+	backlinks := map[string][]string{
+		"hub":    {"page-1", "page-2", "page-3"},
+		"page-1": {"hub", "page-3"},
+		"page-2": {"hub", "page-1"},
+		"page-3": {"hub", "page-2"},
+	}
+	outlinks := map[string]int{
+		"hub":    3,
+		"page-1": 2,
+		"page-2": 2,
+		"page-3": 2,
+	}
+	return backlinks, outlinks
+}
+
+func TestSimulateNodeRemoval(t *testing.T) {
+	backlinks, outlinks := hubGraph()
+	before, after := SimulateNodeRemoval("hub", backlinks, outlinks, New().SetIterations(100))
+
+	if len(before) != 4 || len(after) != 3 {
+		t.Fatalf("expected 4 results before and 3 after, got %d and %d", len(before), len(after))
+	}
+	prev := map[string]float64{}
+	for _, r := range before {
+		prev[r.URL] = r.Rank
+	}
+
+	var gained float64
+	for _, r := range after {
+		if r.URL == "hub" {
+			t.Fatal("removed page should not appear in after results")
+		}
+		gained += r.Rank - prev[r.URL]
+	}
+	if math.Abs(gained-prev["hub"]) > 1e-3 {
+		t.Errorf("rank gained by remaining pages %.6f should match hub rank %.6f", gained, prev["hub"])
+	}
+
+	if len(backlinks["page-1"]) != 2 || outlinks["page-1"] != 2 {
+		t.Error("SimulateNodeRemoval must not modify its inputs")
+	}
+}
+
+func TestFindCriticalDependencies(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calc := New()
+	results := calc.Calculate(backlinks, outlinks)
+
+	all := FindCriticalDependencies(results, backlinks, outlinks, calc, 0)
+	if len(all) != 8 {
+		t.Fatalf("expected all 8 links with zero threshold, got %d", len(all))
+	}
+	if all[0].Source != "page-d" || all[0].Target != "page-c" {
+		t.Errorf("most critical link should be page-d -> page-c, got %s -> %s", all[0].Source, all[0].Target)
+	}
+	ranks := ScoreMap(results)
+	for i, dep := range all {
+		if i > 0 && dep.RankLoss > all[i-1].RankLoss {
+			t.Fatalf("dependencies not sorted at %d", i)
+		}
+		if want := ranks[dep.Source] / float64(outlinks[dep.Source]); dep.RankLoss != want {
+			t.Errorf("%s -> %s: RankLoss %v, want rank/outlinks %v", dep.Source, dep.Target, dep.RankLoss, want)
+		}
+	}
+
+	strict := FindCriticalDependencies(results, backlinks, outlinks, calc, 0.2)
+	if len(strict) != 1 {
+		t.Errorf("expected only page-d -> page-c above 0.2, got %v", strict)
+	}
+}
+
+func TestExplainContributionsSumToRank(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calc := New()
+	ranks := ScoreMap(calc.Calculate(backlinks, outlinks))
+
+	got := calc.Explain("page-d", backlinks, outlinks, ranks)
+	if !strings.HasPrefix(got, "Page page-d has rank ") || !strings.Contains(got, "teleportation contributes") {
+		t.Fatalf("unexpected explanation %q", got)
+	}
+	for _, src := range backlinks["page-d"] {
+		if !strings.Contains(got, src+" (rank=") {
+			t.Errorf("explanation %q does not list %s", got, src)
+		}
+	}
+
+	matches := regexp.MustCompile(`contributes ([0-9.e+-]+)`).FindAllStringSubmatch(got, -1)
+	if len(matches) != len(backlinks["page-d"])+1 {
+		t.Fatalf("expected %d contributions in %q", len(backlinks["page-d"])+1, got)
+	}
+	var sum float64
+	prev := math.Inf(1)
+	for i, m := range matches {
+		v, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			t.Fatalf("bad number %q: %v", m[1], err)
+		}
+		if i < len(matches)-1 && v > prev {
+			t.Errorf("inlink contributions not in descending order: %q", got)
+		}
+		prev = v
+		sum += v
+	}
+	if math.Abs(sum-ranks["page-d"]) > 1e-3 {
+		t.Errorf("contributions sum to %v, rank is %v", sum, ranks["page-d"])
+	}
+}
+
+func TestAnalyzeInlinkSources(t *testing.T) {
+	backlinks := map[string][]string{
+		"bought": {"s1", "s2", "s3", "s4", "s5", "s6", "s1"},
+		"earned": {"e1", "e2", "e3", "e4", "e5"},
+	}
+	outlinks := map[string]int{
+		"s1": 50, "s2": 50, "s3": 51, "s4": 50, "s5": 49, "s6": 50,
+		"e1": 3, "e2": 12, "e3": 40, "e4": 7, "e5": 150,
+	}
+
+	bought := AnalyzeInlinkSources("bought", backlinks, outlinks)
+	if bought.SourceCount != 6 || bought.OutlinkCountMean != 50 || bought.OutlinkCountMedian != 50 {
+		t.Errorf("unexpected summary %+v", bought)
+	}
+	if want := math.Sqrt(2.0 / 6); math.Abs(bought.OutlinkCountStdDev-want) > 1e-12 {
+		t.Errorf("std dev %v, want %v", bought.OutlinkCountStdDev, want)
+	}
+	if !bought.SuspiciouslyUniform {
+		t.Error("near-identical source outlink counts should be flagged")
+	}
+
+	earned := AnalyzeInlinkSources("earned", backlinks, outlinks)
+	if earned.SuspiciouslyUniform || earned.OutlinkCountMedian != 12 {
+		t.Errorf("varied sources misjudged: %+v", earned)
+	}
+	if few := AnalyzeInlinkSources("x", map[string][]string{"x": {"s1", "s2"}}, outlinks); few.SuspiciouslyUniform {
+		t.Error("two sources are too few to flag")
+	}
+	if none := AnalyzeInlinkSources("missing", backlinks, outlinks); none != (InlinkAnalysis{}) {
+		t.Errorf("page without inlinks gave %+v", none)
+	}
+}
@@ -0,0 +1,58 @@
+package pagerank
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// AnonymizeGraph replaces every URL in backlinks with a random UUID.
+// urlMapping maps each original URL to its UUID.
+func AnonymizeGraph(backlinks map[string][]string) (anonBacklinks map[string][]string, urlMapping map[string]string) {
+	urlMapping = make(map[string]string)
+	anon := func(url string) string {
+		id, ok := urlMapping[url]
+		if !ok {
+			id = newUUID()
+			urlMapping[url] = id
+		}
+		return id
+	}
+
+	anonBacklinks = make(map[string][]string, len(backlinks))
+	for target, sources := range backlinks {
+		mapped := make([]string, len(sources))
+		for i, src := range sources {
+			mapped[i] = anon(src)
+		}
+		anonBacklinks[anon(target)] = mapped
+	}
+	return anonBacklinks, urlMapping
+}
+
+// DeAnonymizeResults maps anonymized result URLs back to the originals
+// using the mapping returned by AnonymizeGraph. Unknown IDs are kept as is.
+func DeAnonymizeResults(results []Result, urlMapping map[string]string) []Result {
+	original := make(map[string]string, len(urlMapping))
+	for url, id := range urlMapping {
+		original[id] = url
+	}
+	out := make([]Result, len(results))
+	for i, r := range results {
+		if url, ok := original[r.URL]; ok {
+			r.URL = url
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID.
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("pagerank: crypto/rand failed: " + err.Error())
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
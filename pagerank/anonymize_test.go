@@ -0,0 +1,44 @@
+package pagerank
+
+import (
+	"regexp"
+	"testing"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestAnonymizeRoundTrip(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	anon, mapping := AnonymizeGraph(backlinks)
+
+	if len(mapping) != 4 {
+		t.Fatalf("expected 4 mapped URLs, got %d", len(mapping))
+	}
+	for url, id := range mapping {
+		if !uuidPattern.MatchString(id) {
+			t.Errorf("%s mapped to non-UUID %q", url, id)
+		}
+		if _, leaked := anon[url]; leaked {
+			t.Errorf("original URL %s present in anonymized graph", url)
+		}
+	}
+
+	anonOut := make(map[string]int, len(outlinks))
+	for url, n := range outlinks {
+		anonOut[mapping[url]] = n
+	}
+
+	want := map[string]float64{}
+	for _, r := range New().Calculate(backlinks, outlinks) {
+		want[r.URL] = r.Rank
+	}
+	got := DeAnonymizeResults(New().Calculate(anon, anonOut), mapping)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(got))
+	}
+	for _, r := range got {
+		if w, ok := want[r.URL]; !ok || w != r.Rank {
+			t.Errorf("%s: got rank %v, want %v", r.URL, r.Rank, w)
+		}
+	}
+}
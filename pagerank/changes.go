@@ -0,0 +1,76 @@
+package pagerank
+
+import "sort"
+
+// RankingChange describes how one page moved between two rankings.
+// Positions are 1-based; -1 means the page was absent from that ranking.
+// PositionDelta is positive for pages that moved up, and 0 for pages
+// that appeared or disappeared.
+type RankingChange struct {
+	URL              string
+	PreviousPosition int
+	CurrentPosition  int
+	PositionDelta    int
+	RankDelta        float64
+}
+
+// RankingChanges compares two rankings, each ordered by rank whatever
+// their input order, and lists every page in either, biggest movers first.
+func RankingChanges(before, after []Result) []RankingChange {
+	prev := positions(before)
+	cur := positions(after)
+
+	changes := make([]RankingChange, 0, len(cur))
+	for url, c := range cur {
+		change := RankingChange{URL: url, PreviousPosition: -1, CurrentPosition: c.pos, RankDelta: c.rank}
+		if p, ok := prev[url]; ok {
+			change.PreviousPosition = p.pos
+			change.PositionDelta = p.pos - c.pos
+			change.RankDelta = c.rank - p.rank
+		}
+		changes = append(changes, change)
+	}
+	for url, p := range prev {
+		if _, ok := cur[url]; !ok {
+			changes = append(changes, RankingChange{URL: url, PreviousPosition: p.pos, CurrentPosition: -1, RankDelta: -p.rank})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if da, db := abs(a.PositionDelta), abs(b.PositionDelta); da != db {
+			return da > db
+		}
+		if (a.CurrentPosition < 0) != (b.CurrentPosition < 0) {
+			return b.CurrentPosition < 0
+		}
+		if a.CurrentPosition != b.CurrentPosition {
+			return a.CurrentPosition < b.CurrentPosition
+		}
+		return a.URL < b.URL
+	})
+	return changes
+}
+
+type placement struct {
+	pos  int
+	rank float64
+}
+
+func positions(results []Result) map[string]placement {
+	sorted := make([]Result, len(results))
+	copy(sorted, results)
+	sort.Sort(ByRankDesc(sorted))
+	out := make(map[string]placement, len(sorted))
+	for i, r := range sorted {
+		out[r.URL] = placement{pos: i + 1, rank: r.Rank}
+	}
+	return out
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,51 @@
+package pagerank
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestRankingChanges(t *testing.T) {
+	var before, after []Result
+	for i := 1; i <= 10; i++ {
+		before = append(before, Result{URL: fmt.Sprintf("p%02d", i), Rank: 1 - float64(i)/100})
+	}
+	// p10 jumps from 10th to 2nd; p09 drops out; "new" enters last.
+	for _, r := range before {
+		switch r.URL {
+		case "p10":
+			r.Rank = 0.985
+		case "p09":
+			continue
+		}
+		after = append(after, r)
+	}
+	after = append(after, Result{URL: "new", Rank: 0.5})
+
+	changes := RankingChanges(before, after)
+	if len(changes) != 11 {
+		t.Fatalf("expected 11 changes, got %d", len(changes))
+	}
+	top := changes[0]
+	if top.URL != "p10" || top.PreviousPosition != 10 || top.CurrentPosition != 2 || top.PositionDelta != 8 {
+		t.Errorf("biggest mover = %+v, want p10 from 10 to 2", top)
+	}
+	if math.Abs(top.RankDelta-0.085) > 1e-12 {
+		t.Errorf("p10 rank delta = %v, want 0.085", top.RankDelta)
+	}
+
+	byURL := make(map[string]RankingChange)
+	for _, c := range changes {
+		byURL[c.URL] = c
+	}
+	if c := byURL["new"]; c.PreviousPosition != -1 || c.CurrentPosition != 10 || c.RankDelta != 0.5 {
+		t.Errorf("new page = %+v", c)
+	}
+	if c := byURL["p09"]; c.CurrentPosition != -1 || c.PreviousPosition != 9 {
+		t.Errorf("dropped page = %+v", c)
+	}
+	if c := byURL["p03"]; c.PositionDelta != -1 {
+		t.Errorf("p03 should drop one place, got %+v", c)
+	}
+}
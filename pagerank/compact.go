@@ -0,0 +1,82 @@
+package pagerank
+
+import "sort"
+
+// CompactGraph is the link graph with URLs renumbered to dense int32 IDs,
+// in sorted URL order. Targets[id] lists the pages id links to and
+// OutlinkCounts[id] is its outlink count as given to Compact.
+type CompactGraph struct {
+	URLByID       []string
+	IDByURL       map[string]int32
+	Targets       [][]int32
+	OutlinkCounts []int32
+}
+
+func Compact(backlinks map[string][]string, outlinksCount map[string]int) *CompactGraph {
+	urls := collectURLs(backlinks, outlinksCount)
+	g := &CompactGraph{
+		URLByID:       urls,
+		IDByURL:       make(map[string]int32, len(urls)),
+		Targets:       make([][]int32, len(urls)),
+		OutlinkCounts: make([]int32, len(urls)),
+	}
+	for i, url := range urls {
+		g.IDByURL[url] = int32(i)
+		g.OutlinkCounts[i] = int32(outlinksCount[url])
+	}
+	for _, url := range urls {
+		target := g.IDByURL[url]
+		for _, src := range backlinks[url] {
+			id := g.IDByURL[src]
+			g.Targets[id] = append(g.Targets[id], target)
+		}
+	}
+	return g
+}
+
+func (g *CompactGraph) NumNodes() int { return len(g.URLByID) }
+
+// CalculateCompact runs the same iteration as Calculate over a compact
+// graph, pushing each page's rank along its outlinks. Like CalculateCSR it
+// uses only the damping factor and iteration count.
+func (c *Calculator) CalculateCompact(g *CompactGraph) []Result {
+	total := g.NumNodes()
+	if total == 0 {
+		return []Result{}
+	}
+
+	share := make([]float64, total)
+	rank := make([]float64, total)
+	next := make([]float64, total)
+	for i := range rank {
+		rank[i] = 1.0 / float64(total)
+	}
+	teleport := (1.0 - c.damping) / float64(total)
+
+	for it := 0; it < c.iterations; it++ {
+		for i, r := range rank {
+			out := float64(g.OutlinkCounts[i])
+			if out == 0 {
+				out = 1
+			}
+			share[i] = r / out
+			next[i] = 0
+		}
+		for src, targets := range g.Targets {
+			for _, t := range targets {
+				next[t] += share[src]
+			}
+		}
+		for i := range next {
+			next[i] = teleport + c.damping*next[i]
+		}
+		rank, next = next, rank
+	}
+
+	results := make([]Result, total)
+	for i, url := range g.URLByID {
+		results[i] = Result{URL: url, Rank: rank[i]}
+	}
+	sort.Sort(ByRankDesc(results))
+	return results
+}
@@ -0,0 +1,54 @@
+package pagerank
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	g := Compact(backlinks, outlinks)
+	if g.NumNodes() != 4 || g.URLByID[0] != "page-a" || g.IDByURL["page-d"] != 3 {
+		t.Fatalf("unexpected numbering %v", g.URLByID)
+	}
+	// page-c links to a, b and d.
+	if got := g.Targets[g.IDByURL["page-c"]]; len(got) != 3 || g.OutlinkCounts[2] != 3 {
+		t.Errorf("page-c targets %v, count %d", got, g.OutlinkCounts[2])
+	}
+}
+
+func TestCalculateCompactMatchesCalculate(t *testing.T) {
+	graphs := map[string]func() (map[string][]string, map[string]int){
+		"sample":    sampleGraph,
+		"synthetic": func() (map[string][]string, map[string]int) { return syntheticGraph(500, 4, 1) },
+		"powerlaw":  func() (map[string][]string, map[string]int) { return GeneratePowerLawGraph(500, 3, 2) },
+	}
+	for name, build := range graphs {
+		backlinks, outlinks := build()
+		want := New().Calculate(backlinks, outlinks)
+		got := New().CalculateCompact(Compact(backlinks, outlinks))
+
+		if len(got) != len(want) {
+			t.Fatalf("%s: expected %d results, got %d", name, len(want), len(got))
+		}
+		// Summation order differs, so compare by URL: pages that tie in
+		// Calculate may swap places.
+		ranks := ScoreMap(got)
+		for _, r := range want {
+			if math.Abs(ranks[r.URL]-r.Rank) > 1e-12 {
+				t.Fatalf("%s: %s = %v, want %v", name, r.URL, ranks[r.URL], r.Rank)
+			}
+		}
+	}
+}
+
+func BenchmarkCalculateCompact(b *testing.B) {
+	backlinks, outlinks := syntheticGraph(10000, 8, 1)
+	g := Compact(backlinks, outlinks)
+	calc := New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		calc.CalculateCompact(g)
+	}
+}
@@ -0,0 +1,195 @@
+package pagerank
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// Environment variables read by NewFromEnv.
+const (
+	EnvDamping    = "PAGE_RANK_DAMPING"
+	EnvIterations = "PAGE_RANK_ITERATIONS"
+)
+
+// calculatorConfig is the JSON form of a Calculator. The sampling RNG,
+// fault injector, outlink normalizer, link classifier, rank logger and
+// diagnostic writer cannot be serialized and are left out.
+type calculatorConfig struct {
+	Damping            float64                       `json:"damping"`
+	AdaptiveDamping    *adaptiveDampingConfig        `json:"adaptive_damping,omitempty"`
+	Iterations         int                           `json:"iterations"`
+	Tolerance          float64                       `json:"tolerance,omitempty"`
+	MaxInlinksPerNode  int                           `json:"max_inlinks_per_node,omitempty"`
+	MaxRankCap         float64                       `json:"max_rank_cap,omitempty"`
+	RestartProbability map[string]float64            `json:"restart_probabilities,omitempty"`
+	TeleportMatrix     map[string]map[string]float64 `json:"teleport_matrix,omitempty"`
+	MaxNodes           int                           `json:"max_nodes,omitempty"`
+	MaxEdges           int                           `json:"max_edges,omitempty"`
+	MaxMemoryBytes     int64                         `json:"max_memory_bytes,omitempty"`
+	Precision          string                        `json:"precision,omitempty"`
+	IterationMethod    string                        `json:"iteration_method,omitempty"`
+	FullyCorrect       bool                          `json:"fully_correct,omitempty"`
+	SpamScores         map[string]float64            `json:"spam_scores,omitempty"`
+	Undirected         bool                          `json:"undirected,omitempty"`
+	AbsorbingNodes     []string                      `json:"absorbing_nodes,omitempty"`
+	FocusSet           []string                      `json:"focus_set,omitempty"`
+	History            bool                          `json:"history,omitempty"`
+}
+
+type adaptiveDampingConfig struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+func (c *Calculator) MarshalJSON() ([]byte, error) {
+	cfg := calculatorConfig{
+		Damping:            c.damping,
+		Iterations:         c.iterations,
+		Tolerance:          c.tolerance,
+		MaxInlinksPerNode:  c.maxInlinks,
+		MaxRankCap:         c.maxRankCap,
+		RestartProbability: c.restart,
+		TeleportMatrix:     c.teleport,
+		MaxNodes:           c.maxNodes,
+		MaxEdges:           c.maxEdges,
+		MaxMemoryBytes:     c.maxMemory,
+		SpamScores:         c.spam,
+		Undirected:         c.undirected,
+		History:            c.historyEnabled,
+		FullyCorrect:       c.fullyCorrect,
+	}
+	if c.method != Jacobi {
+		cfg.IterationMethod = c.method.String()
+	}
+	if c.adaptive != nil {
+		cfg.AdaptiveDamping = &adaptiveDampingConfig{Min: c.adaptive.min, Max: c.adaptive.max}
+	}
+	if c.precision != Float64 {
+		cfg.Precision = c.precision.String()
+	}
+	for url := range c.absorbing {
+		cfg.AbsorbingNodes = append(cfg.AbsorbingNodes, url)
+	}
+	sort.Strings(cfg.AbsorbingNodes)
+	for url := range c.focus {
+		cfg.FocusSet = append(cfg.FocusSet, url)
+	}
+	sort.Strings(cfg.FocusSet)
+	return json.Marshal(cfg)
+}
+
+// UnmarshalJSON replaces c's configuration with the one in data. Settings
+// that are missing keep New's defaults; out-of-range values are errors.
+// Settings that JSON cannot carry, like the sampling RNG, are reset.
+func (c *Calculator) UnmarshalJSON(data []byte) error {
+	def := New()
+	cfg := calculatorConfig{Damping: def.damping, Iterations: def.iterations}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("pagerank: decode calculator config: %w", err)
+	}
+
+	next := New()
+	if next.SetDamping(cfg.Damping).damping != cfg.Damping {
+		return fmt.Errorf("pagerank: damping %g outside (0, 1)", cfg.Damping)
+	}
+	if a := cfg.AdaptiveDamping; a != nil && next.SetAdaptiveDamping(cfg.Damping, a.Min, a.Max).adaptive == nil {
+		return fmt.Errorf("pagerank: adaptive damping [%g, %g] does not bracket damping %g in (0, 1)", a.Min, a.Max, cfg.Damping)
+	}
+	if next.SetIterations(cfg.Iterations).iterations != cfg.Iterations {
+		return fmt.Errorf("pagerank: iterations %d must be positive", cfg.Iterations)
+	}
+	for url, s := range cfg.SpamScores {
+		if s < 0 || s > 1 {
+			return fmt.Errorf("pagerank: spam score %g for %s outside [0, 1]", s, url)
+		}
+	}
+	if cfg.Tolerance < 0 || cfg.MaxInlinksPerNode < 0 || cfg.MaxRankCap < 0 || cfg.MaxMemoryBytes < 0 ||
+		cfg.MaxNodes < 0 || cfg.MaxEdges < 0 {
+		return fmt.Errorf("pagerank: negative limit in calculator config")
+	}
+	next.SetTolerance(cfg.Tolerance).
+		SetMaxInlinksPerNode(cfg.MaxInlinksPerNode).
+		SetMaxRankCap(cfg.MaxRankCap).
+		SetRestartProbabilities(cfg.RestartProbability).
+		SetMaxNodes(cfg.MaxNodes).
+		SetMaxEdges(cfg.MaxEdges).
+		SetMaxMemoryBytes(cfg.MaxMemoryBytes).
+		SetSpamScores(cfg.SpamScores).
+		SetUndirected(cfg.Undirected).
+		SetAbsorbingNodes(cfg.AbsorbingNodes).
+		SetFocusSet(cfg.FocusSet).
+		SetHistoryEnabled(cfg.History).
+		SetFullyCorrectFormulation(cfg.FullyCorrect)
+	// Rows saved by MarshalJSON are already normalized, and normalizing
+	// them again could move the last bit, so those are kept as written.
+	next.SetCustomTeleportMatrix(cfg.TeleportMatrix)
+	for from := range next.teleport {
+		if row := cfg.TeleportMatrix[from]; isDistribution(row) {
+			next.teleport[from] = row
+		}
+	}
+	switch cfg.Precision {
+	case "", "float64":
+	case "float32":
+		next.SetPrecision(Float32)
+	default:
+		return fmt.Errorf("pagerank: unknown precision %q", cfg.Precision)
+	}
+	switch cfg.IterationMethod {
+	case "", "jacobi":
+	case "gauss-seidel":
+		next.SetIterationMethod(GaussSeidel)
+	default:
+		return fmt.Errorf("pagerank: unknown iteration method %q", cfg.IterationMethod)
+	}
+	*c = *next
+	return nil
+}
+
+// LoadCalculatorConfig reads a calculator saved with json.Marshal.
+func LoadCalculatorConfig(path string) (*Calculator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: read calculator config: %w", err)
+	}
+	c := New()
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewFromEnv returns New's defaults overridden by PAGE_RANK_DAMPING and
+// PAGE_RANK_ITERATIONS where those are set and non-empty. A value that
+// does not parse or is out of range is an error.
+func NewFromEnv() (*Calculator, error) {
+	c := New()
+	if v := os.Getenv(EnvDamping); v != "" {
+		d, err := strconv.ParseFloat(v, 64)
+		if err != nil || c.SetDamping(d).damping != d {
+			return nil, fmt.Errorf("pagerank: %s=%q: want a number strictly between 0 and 1", EnvDamping, v)
+		}
+	}
+	if v := os.Getenv(EnvIterations); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || c.SetIterations(n).iterations != n {
+			return nil, fmt.Errorf("pagerank: %s=%q: want a positive integer", EnvIterations, v)
+		}
+	}
+	return c, nil
+}
+
+func isDistribution(row map[string]float64) bool {
+	var sum float64
+	for _, p := range row {
+		if p <= 0 {
+			return false
+		}
+		sum += p
+	}
+	return math.Abs(sum-1) < 1e-9
+}
@@ -0,0 +1,101 @@
+package pagerank
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCalculatorJSONRoundTrip(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calc := New().SetAdaptiveDamping(0.9, 0.8, 0.95).SetIterations(30).SetTolerance(1e-10).SetMaxRankCap(0.35).
+		SetRestartProbabilities(map[string]float64{"page-a": 0.4}).
+		SetCustomTeleportMatrix(map[string]map[string]float64{"page-b": {"page-c": 1, "page-d": 2}}).
+		SetMaxMemoryBytes(1 << 20).
+		SetMaxNodes(100).
+		SetMaxEdges(100).
+		SetPrecision(Float32).
+		SetIterationMethod(GaussSeidel).
+		SetFullyCorrectFormulation(true).
+		SetSpamScores(map[string]float64{"page-b": 0.5}).
+		SetUndirected(true).
+		SetAbsorbingNodes([]string{"page-d"}).
+		SetFocusSet([]string{"page-a", "page-c"})
+	want := calc.Calculate(backlinks, outlinks)
+
+	data, err := json.Marshal(calc)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "calc.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCalculatorConfig(path)
+	if err != nil {
+		t.Fatalf("LoadCalculatorConfig: %v", err)
+	}
+	if loaded.String() != calc.String() {
+		t.Errorf("config changed:\n got %s\nwant %s", loaded, calc)
+	}
+	if got := loaded.Calculate(backlinks, outlinks); !reflect.DeepEqual(got, want) {
+		t.Errorf("results changed:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestCalculatorUnmarshalJSON(t *testing.T) {
+	var calc Calculator
+	if err := json.Unmarshal([]byte(`{"iterations": 10}`), &calc); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if calc.Damping() != 0.85 || calc.Iterations() != 10 {
+		t.Errorf("got damping=%v iterations=%d", calc.Damping(), calc.Iterations())
+	}
+	for _, doc := range []string{`{"damping": 1.5}`, `{"iterations": -1}`, `{"precision": "float16"}`, `{"iteration_method": "sor"}`, `{"max_rank_cap": -1}`, `{"adaptive_damping": {"min": 0.9, "max": 0.95}}`, `[]`} {
+		if err := json.Unmarshal([]byte(doc), New()); err == nil {
+			t.Errorf("expected an error for %s", doc)
+		}
+	}
+	if _, err := LoadCalculatorConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil || !strings.Contains(err.Error(), "read calculator config") {
+		t.Errorf("expected a read error, got %v", err)
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv(EnvDamping, "0.9")
+	t.Setenv(EnvIterations, "25")
+	calc, err := NewFromEnv()
+	if err != nil {
+		t.Fatalf("NewFromEnv: %v", err)
+	}
+	if calc.Damping() != 0.9 || calc.Iterations() != 25 {
+		t.Errorf("got damping %v, iterations %d", calc.Damping(), calc.Iterations())
+	}
+
+	t.Setenv(EnvDamping, "")
+	t.Setenv(EnvIterations, "")
+	calc, err = NewFromEnv()
+	if err != nil {
+		t.Fatalf("NewFromEnv with empty variables: %v", err)
+	}
+	if def := New(); calc.Damping() != def.Damping() || calc.Iterations() != def.Iterations() {
+		t.Errorf("empty variables should keep the defaults, got %s", calc)
+	}
+
+	for name, value := range map[string]string{EnvDamping: "two", EnvIterations: "0"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			_, err := NewFromEnv()
+			if err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("%s=%s: error %v should name the variable", name, value, err)
+			}
+		})
+	}
+	t.Setenv(EnvDamping, "1")
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("damping 1 should be rejected")
+	}
+}
@@ -0,0 +1,115 @@
+package pagerank
+
+// ContractLowRankNodes removes every page ranked below minRank. Links into
+// a removed page are redirected to its lowest-ranked retained outlink
+// target, so the rank it would have forwarded still reaches the graph;
+// links out of a removed page are dropped. A removed page with no retained
+// target simply loses its inlinks. Redirected links keep their place in
+// the source's outlink count, even when they duplicate an existing link,
+// so each source still spreads its rank the same way; counts only drop
+// for links that are removed outright, and never below the links kept.
+func ContractLowRankNodes(backlinks map[string][]string, outlinksCount map[string]int, results []Result, minRank float64) (contracted map[string][]string, contractedCounts map[string]int, removedCount int) {
+	rank := make(map[string]float64, len(results))
+	for _, r := range results {
+		rank[r.URL] = r.Rank
+	}
+	removed := make(map[string]bool)
+	for _, url := range collectURLs(backlinks, outlinksCount) {
+		if rank[url] < minRank {
+			removed[url] = true
+		}
+	}
+
+	forward := forwardLinks(backlinks)
+	redirect := make(map[string]string, len(removed))
+	for url := range removed {
+		best, found := "", false
+		for _, t := range forward[url] {
+			if removed[t] {
+				continue
+			}
+			if !found || rank[t] < rank[best] || (rank[t] == rank[best] && t < best) {
+				best, found = t, true
+			}
+		}
+		if found {
+			redirect[url] = best
+		}
+	}
+
+	contracted = make(map[string][]string, len(backlinks))
+	kept := make(map[string]int)
+	dropped := make(map[string]int)
+	for _, target := range collectURLs(backlinks, nil) {
+		for _, src := range backlinks[target] {
+			if removed[src] {
+				continue
+			}
+			to := target
+			if removed[target] {
+				r, ok := redirect[target]
+				if !ok {
+					dropped[src]++
+					continue
+				}
+				to = r
+			}
+			contracted[to] = append(contracted[to], src)
+			kept[src]++
+		}
+	}
+
+	// A count missing from outlinksCount, or lower than the links
+	// backlinks lists, is raised to the links actually kept.
+	contractedCounts = make(map[string]int, len(outlinksCount))
+	for _, url := range collectURLs(backlinks, outlinksCount) {
+		if removed[url] {
+			continue
+		}
+		contractedCounts[url] = max(outlinksCount[url]-dropped[url], kept[url])
+	}
+	return contracted, contractedCounts, len(removed)
+}
+
+// ApplyCanonicalMap rewrites every alias URL to its canonical form, where
+// canonical[alias] is the page the alias redirects to. Chains of aliases
+// are followed to their end. Links that become duplicates, or that become
+// self-links because both ends merged into one page, are dropped and the
+// source's outlink count reduced to match. A canonical page's count is the
+// sum of its aliases' counts.
+func ApplyCanonicalMap(backlinks map[string][]string, outlinksCount map[string]int, canonical map[string]string) (map[string][]string, map[string]int) {
+	resolve := func(url string) string {
+		seen := map[string]bool{url: true}
+		for {
+			next, ok := canonical[url]
+			if !ok || seen[next] {
+				return url
+			}
+			seen[next] = true
+			url = next
+		}
+	}
+
+	counts := make(map[string]int, len(outlinksCount))
+	for url, n := range outlinksCount {
+		counts[resolve(url)] += n
+	}
+	merged := make(map[string][]string, len(backlinks))
+	seen := make(map[edge]bool)
+	for _, target := range collectURLs(backlinks, nil) {
+		to := resolve(target)
+		for _, src := range backlinks[target] {
+			from := resolve(src)
+			e := edge{source: from, target: to}
+			if seen[e] || (from == to && src != target) {
+				if counts[from] > 0 {
+					counts[from]--
+				}
+				continue
+			}
+			seen[e] = true
+			merged[to] = append(merged[to], from)
+		}
+	}
+	return merged, counts
+}
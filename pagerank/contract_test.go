@@ -0,0 +1,142 @@
+package pagerank
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// hubWithTail returns a ring of core pages that all link to a hub (which
+// links back to every one of them), plus tail pages hanging off evenly
+// spaced core pages and linking to the hub.
+func hubWithTail(core, tail int) (map[string][]string, map[string]int, []string) {
+	backlinks := map[string][]string{}
+	outlinks := map[string]int{}
+	link := func(from, to string) {
+		backlinks[to] = append(backlinks[to], from)
+		outlinks[from]++
+	}
+	for i := 0; i < core; i++ {
+		page := fmt.Sprintf("page-%d", i)
+		link(page, fmt.Sprintf("page-%d", (i+1)%core))
+		link(page, "hub")
+		link("hub", page)
+	}
+	var tails []string
+	for i := 0; i < tail; i++ {
+		url := fmt.Sprintf("tail-%d", i)
+		link(fmt.Sprintf("page-%d", i*core/tail), url)
+		link(url, "hub")
+		tails = append(tails, url)
+	}
+	return backlinks, outlinks, tails
+}
+
+func TestContractLowRankNodes(t *testing.T) {
+	backlinks, outlinks, tails := hubWithTail(100, 5)
+	calc := New()
+	results := calc.Calculate(backlinks, outlinks)
+
+	orig := map[string]float64{}
+	for _, r := range results {
+		orig[r.URL] = r.Rank
+	}
+	var minRank float64
+	for _, url := range tails {
+		minRank = math.Max(minRank, orig[url]*1.0001)
+	}
+
+	contracted, counts, removed := ContractLowRankNodes(backlinks, outlinks, results, minRank)
+	if removed != len(tails) {
+		t.Fatalf("expected %d tail nodes removed, got %d", len(tails), removed)
+	}
+	if n := len(collectURLs(contracted, counts)); n != len(results)-removed {
+		t.Fatalf("contracted graph has %d nodes, want %d", n, len(results)-removed)
+	}
+
+	for _, r := range calc.Calculate(contracted, counts) {
+		if orig[r.URL] < minRank {
+			t.Fatalf("removed node %s still present", r.URL)
+		}
+		if drift := math.Abs(r.Rank-orig[r.URL]) / orig[r.URL]; drift > 0.05 {
+			t.Errorf("%s drifted %.1f%% (%.6f -> %.6f)", r.URL, drift*100, orig[r.URL], r.Rank)
+		}
+	}
+}
+
+func TestContractLowRankNodesRedirectsLinks(t *testing.T) {
+	backlinks := map[string][]string{
+		"hub":  {"a", "b", "leaf"},
+		"a":    {"hub"},
+		"b":    {"hub"},
+		"leaf": {"a"},
+	}
+	outlinks := map[string]int{"hub": 2, "a": 2, "b": 1, "leaf": 1}
+	results := []Result{{"hub", 0.5}, {"a", 0.2}, {"b", 0.2}, {"leaf", 0.1}}
+
+	contracted, counts, removed := ContractLowRankNodes(backlinks, outlinks, results, 0.15)
+	if removed != 1 {
+		t.Fatalf("expected 1 removed node, got %d", removed)
+	}
+	if counts["a"] != 2 {
+		t.Errorf("a's redirected link should keep its outlink count at 2, got %d", counts["a"])
+	}
+	if got := contracted["hub"]; len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "a" {
+		t.Errorf("hub backlinks = %v, want [a b a]", got)
+	}
+	if _, ok := counts["leaf"]; ok {
+		t.Error("removed node should not keep an outlink count")
+	}
+}
+
+func TestContractLowRankNodesMissingOutlinkCounts(t *testing.T) {
+	// x and y are missing from the counts; dead is removed with nowhere to
+	// redirect to, so the links into it are dropped.
+	backlinks := map[string][]string{
+		"hub":  {"a", "x"},
+		"a":    {"hub"},
+		"dead": {"x", "y"},
+	}
+	outlinks := map[string]int{"hub": 1, "a": 1, "dead": 0}
+	results := []Result{{"hub", 0.4}, {"a", 0.3}, {"x", 0.1}, {"y", 0.1}, {"dead", 0.01}}
+
+	_, counts, removed := ContractLowRankNodes(backlinks, outlinks, results, 0.05)
+	if removed != 1 {
+		t.Fatalf("expected 1 removed node, got %d", removed)
+	}
+	want := map[string]int{"hub": 1, "a": 1, "x": 1, "y": 0}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("counts = %v, want %v", counts, want)
+	}
+}
+
+func TestApplyCanonicalMapRedirectChain(t *testing.T) {
+	backlinks := map[string][]string{
+		"B": {"A", "D"},
+		"C": {"B", "A"},
+		"A": {"B"},
+	}
+	outlinks := map[string]int{"A": 2, "B": 2, "C": 0, "D": 1}
+
+	merged, counts := ApplyCanonicalMap(backlinks, outlinks, map[string]string{"B": "C"})
+	if _, ok := merged["B"]; ok {
+		t.Fatalf("alias B still has inlinks: %v", merged)
+	}
+	if _, ok := counts["B"]; ok {
+		t.Fatalf("alias B still has an outlink count: %v", counts)
+	}
+	want := map[string][]string{"A": {"C"}, "C": {"A", "D"}}
+	for target, sources := range want {
+		if fmt.Sprint(merged[target]) != fmt.Sprint(sources) {
+			t.Errorf("backlinks[%s] = %v, want %v", target, merged[target], sources)
+		}
+	}
+	// A->B and A->C collapse into one link; B->C becomes a self-link.
+	wantCounts := map[string]int{"A": 1, "C": 1, "D": 1}
+	for url, n := range wantCounts {
+		if counts[url] != n {
+			t.Errorf("outlinks[%s] = %d, want %d", url, counts[url], n)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package pagerank
+
+import "container/heap"
+
+type queueItem struct {
+	url   string
+	rank  float64
+	depth int
+}
+
+// PriorityQueue is a min-heap keyed on negative rank, so Pop yields the
+// highest-ranked URL first. Ties are broken by URL for determinism.
+type PriorityQueue []queueItem
+
+func (pq PriorityQueue) Len() int { return len(pq) }
+func (pq PriorityQueue) Less(i, j int) bool {
+	if pq[i].rank == pq[j].rank {
+		return pq[i].url < pq[j].url
+	}
+	return -pq[i].rank < -pq[j].rank
+}
+func (pq PriorityQueue) Swap(i, j int) { pq[i], pq[j] = pq[j], pq[i] }
+func (pq *PriorityQueue) Push(x any)   { *pq = append(*pq, x.(queueItem)) }
+func (pq *PriorityQueue) Pop() any {
+	old := *pq
+	item := old[len(old)-1]
+	*pq = old[:len(old)-1]
+	return item
+}
+
+// RankGuidedBFS is a crawl frontier that always hands out the
+// highest-ranked URL it has discovered but not yet visited.
+type RankGuidedBFS struct {
+	ranks    map[string]float64
+	maxDepth int
+	queue    PriorityQueue
+	depth    map[string]int
+	visited  map[string]bool
+}
+
+func NewRankGuidedBFS(startURLs []string, ranks map[string]float64, maxDepth int) *RankGuidedBFS {
+	b := &RankGuidedBFS{
+		ranks:    ranks,
+		maxDepth: maxDepth,
+		depth:    make(map[string]int),
+		visited:  make(map[string]bool),
+	}
+	for _, url := range startURLs {
+		b.enqueue(url, 0)
+	}
+	return b
+}
+
+// enqueue queues url at depth, or requeues it if it is still unvisited and
+// depth is shallower than the path it was first found by. The entry left
+// behind at the old depth is skipped by Next.
+func (b *RankGuidedBFS) enqueue(url string, depth int) {
+	if depth > b.maxDepth || b.visited[url] {
+		return
+	}
+	if old, queued := b.depth[url]; queued && old <= depth {
+		return
+	}
+	b.depth[url] = depth
+	heap.Push(&b.queue, queueItem{url: url, rank: b.ranks[url], depth: depth})
+}
+
+// Next returns the highest-ranked URL that has not been visited yet.
+func (b *RankGuidedBFS) Next() (url string, ok bool) {
+	for b.queue.Len() > 0 {
+		item := heap.Pop(&b.queue).(queueItem)
+		if !b.visited[item.url] && item.depth == b.depth[item.url] {
+			return item.url, true
+		}
+	}
+	return "", false
+}
+
+// Visit marks url as visited and queues its neighbors one level deeper.
+func (b *RankGuidedBFS) Visit(url string, neighbors []string) {
+	b.visited[url] = true
+	d, ok := b.depth[url]
+	if !ok {
+		b.depth[url] = 0
+	}
+	for _, n := range neighbors {
+		if !b.visited[n] {
+			b.enqueue(n, d+1)
+		}
+	}
+}
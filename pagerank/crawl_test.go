@@ -0,0 +1,103 @@
+package pagerank
+
+import "testing"
+
+func TestRankGuidedBFSPrefersHighRank(t *testing.T) {
+	links := map[string][]string{
+		"root":      {"low-child", "mid-child"},
+		"mid-child": {"top"},
+		"low-child": {"leaf"},
+	}
+	ranks := map[string]float64{
+		"root":      0.2,
+		"low-child": 0.1,
+		"mid-child": 0.3,
+		"top":       0.9,
+		"leaf":      0.05,
+	}
+
+	b := NewRankGuidedBFS([]string{"root"}, ranks, 5)
+	var order []string
+	for {
+		url, ok := b.Next()
+		if !ok {
+			break
+		}
+		order = append(order, url)
+		b.Visit(url, links[url])
+	}
+
+	want := []string{"root", "mid-child", "top", "low-child", "leaf"}
+	if len(order) != len(want) {
+		t.Fatalf("visited %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("visited %v, want %v", order, want)
+		}
+	}
+}
+
+func TestRankGuidedBFSRespectsMaxDepth(t *testing.T) {
+	links := map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"d"},
+	}
+	b := NewRankGuidedBFS([]string{"a"}, map[string]float64{}, 1)
+	var order []string
+	for {
+		url, ok := b.Next()
+		if !ok {
+			break
+		}
+		order = append(order, url)
+		b.Visit(url, links[url])
+	}
+	if len(order) != 2 || order[0] != "a" || order[1] != "b" {
+		t.Errorf("expected [a b] with maxDepth 1, got %v", order)
+	}
+}
+
+func TestRankGuidedBFSVisitsOnce(t *testing.T) {
+	b := NewRankGuidedBFS([]string{"a", "b"}, map[string]float64{"a": 1, "b": 0.5}, 3)
+	url, _ := b.Next()
+	b.Visit(url, []string{"b", "a"})
+	url, _ = b.Next()
+	b.Visit(url, []string{"a"})
+	if url, ok := b.Next(); ok {
+		t.Errorf("expected frontier to be exhausted, got %s", url)
+	}
+}
+
+func TestRankGuidedBFSLowersDepthOnShorterPath(t *testing.T) {
+	links := map[string][]string{
+		"root":   {"a", "s"},
+		"a":      {"b"},
+		"b":      {"target"},
+		"s":      {"target"},
+		"target": {"leaf"},
+	}
+	ranks := map[string]float64{"a": 0.9, "b": 0.8, "s": 0.1, "target": 0.05, "leaf": 0.01}
+
+	b := NewRankGuidedBFS([]string{"root"}, ranks, 3)
+	var order []string
+	for {
+		url, ok := b.Next()
+		if !ok {
+			break
+		}
+		order = append(order, url)
+		b.Visit(url, links[url])
+	}
+
+	want := []string{"root", "a", "b", "s", "target", "leaf"}
+	if len(order) != len(want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, order)
+		}
+	}
+}
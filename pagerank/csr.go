@@ -0,0 +1,97 @@
+package pagerank
+
+import "sort"
+
+// CSRGraph stores the backlink graph in compressed sparse row form. Row i
+// belongs to URLs[i]; Targets[RowPtr[i]:RowPtr[i+1]] holds the indices of
+// the pages linking to it.
+type CSRGraph struct {
+	Targets  []int32
+	RowPtr   []int32
+	URLs     []string
+	URLIndex map[string]int32
+}
+
+func BuildCSR(backlinks map[string][]string) *CSRGraph {
+	seen := make(map[string]bool)
+	edges := 0
+	for url, sources := range backlinks {
+		seen[url] = true
+		for _, src := range sources {
+			seen[src] = true
+		}
+		edges += len(sources)
+	}
+
+	g := &CSRGraph{
+		Targets:  make([]int32, 0, edges),
+		RowPtr:   make([]int32, 1, len(seen)+1),
+		URLs:     make([]string, 0, len(seen)),
+		URLIndex: make(map[string]int32, len(seen)),
+	}
+	for url := range seen {
+		g.URLs = append(g.URLs, url)
+	}
+	sort.Strings(g.URLs)
+	for i, url := range g.URLs {
+		g.URLIndex[url] = int32(i)
+	}
+	for _, url := range g.URLs {
+		for _, src := range backlinks[url] {
+			g.Targets = append(g.Targets, g.URLIndex[src])
+		}
+		g.RowPtr = append(g.RowPtr, int32(len(g.Targets)))
+	}
+	return g
+}
+
+func (g *CSRGraph) NumNodes() int { return len(g.URLs) }
+func (g *CSRGraph) NumEdges() int { return len(g.Targets) }
+
+// CalculateCSR runs the basic power iteration over a CSR graph, with out
+// degrees derived from the edges themselves. It uses only the damping
+// factor and iteration count: tolerance, restart probabilities, the
+// teleport matrix, the rank cap, precision, the outlink normalizer, link
+// classifier, spam scores, absorbing nodes, adaptive damping, the
+// iteration method and the fully correct formulation are all ignored.
+func (c *Calculator) CalculateCSR(g *CSRGraph) []Result {
+	total := g.NumNodes()
+	if total == 0 {
+		return []Result{}
+	}
+
+	out := make([]float64, total)
+	for _, src := range g.Targets {
+		out[src]++
+	}
+	for i := range out {
+		if out[i] == 0 {
+			out[i] = 1
+		}
+	}
+
+	rank := make([]float64, total)
+	next := make([]float64, total)
+	for i := range rank {
+		rank[i] = 1.0 / float64(total)
+	}
+	teleport := (1.0 - c.damping) / float64(total)
+
+	for it := 0; it < c.iterations; it++ {
+		for i := 0; i < total; i++ {
+			var contrib float64
+			for _, src := range g.Targets[g.RowPtr[i]:g.RowPtr[i+1]] {
+				contrib += rank[src] / out[src]
+			}
+			next[i] = teleport + c.damping*contrib
+		}
+		rank, next = next, rank
+	}
+
+	results := make([]Result, total)
+	for i, url := range g.URLs {
+		results[i] = Result{URL: url, Rank: rank[i]}
+	}
+	sort.Sort(ByRankDesc(results))
+	return results
+}
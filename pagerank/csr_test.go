@@ -0,0 +1,89 @@
+package pagerank
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func syntheticGraph(n, degree int, seed int64) (map[string][]string, map[string]int) {
+	rng := rand.New(rand.NewSource(seed))
+	backlinks := make(map[string][]string, n)
+	outlinks := make(map[string]int, n)
+	for i := 0; i < n; i++ {
+		src := fmt.Sprintf("page-%d", i)
+		for j := 0; j < degree; j++ {
+			dst := fmt.Sprintf("page-%d", rng.Intn(n))
+			backlinks[dst] = append(backlinks[dst], src)
+			outlinks[src]++
+		}
+	}
+	return backlinks, outlinks
+}
+
+func TestBuildCSR(t *testing.T) {
+	backlinks, _ := sampleGraph()
+	g := BuildCSR(backlinks)
+
+	if g.NumNodes() != 4 {
+		t.Fatalf("expected 4 nodes, got %d", g.NumNodes())
+	}
+	if g.NumEdges() != 8 {
+		t.Fatalf("expected 8 edges, got %d", g.NumEdges())
+	}
+	for url, sources := range backlinks {
+		i := g.URLIndex[url]
+		row := g.Targets[g.RowPtr[i]:g.RowPtr[i+1]]
+		if len(row) != len(sources) {
+			t.Fatalf("row %s has %d entries, want %d", url, len(row), len(sources))
+		}
+		for k, src := range sources {
+			if g.URLs[row[k]] != src {
+				t.Errorf("row %s entry %d = %s, want %s", url, k, g.URLs[row[k]], src)
+			}
+		}
+	}
+}
+
+func TestCalculateCSRMatchesCalculate(t *testing.T) {
+	graphs := map[string]func() (map[string][]string, map[string]int){
+		"sample":    sampleGraph,
+		"synthetic": func() (map[string][]string, map[string]int) { return syntheticGraph(500, 4, 1) },
+	}
+	for name, build := range graphs {
+		backlinks, outlinks := build()
+		want := New().Calculate(backlinks, outlinks)
+		got := New().CalculateCSR(BuildCSR(backlinks))
+
+		if len(got) != len(want) {
+			t.Fatalf("%s: expected %d results, got %d", name, len(want), len(got))
+		}
+		for i := range want {
+			if got[i].URL != want[i].URL || math.Abs(got[i].Rank-want[i].Rank) > 1e-12 {
+				t.Fatalf("%s: result %d = %v, want %v", name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func BenchmarkCalculateMap(b *testing.B) {
+	backlinks, outlinks := syntheticGraph(10000, 8, 1)
+	calc := New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		calc.Calculate(backlinks, outlinks)
+	}
+}
+
+func BenchmarkCalculateCSR(b *testing.B) {
+	backlinks, _ := syntheticGraph(10000, 8, 1)
+	g := BuildCSR(backlinks)
+	calc := New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		calc.CalculateCSR(g)
+	}
+}
@@ -0,0 +1,106 @@
+package pagerank
+
+import "sort"
+
+// EdgeRemoval is a link BreakCycles cut, from Source to Target.
+type EdgeRemoval struct {
+	Source string
+	Target string
+}
+
+// DetectRankCycles returns the strongly connected components that hold
+// more than minRankFraction of the total rank, highest share first. Each
+// is sorted by URL. Single pages count only if they link to themselves.
+func DetectRankCycles(backlinks map[string][]string, results []Result, minRankFraction float64) [][]string {
+	rank := ScoreMap(results)
+	var total float64
+	for _, r := range results {
+		total += r.Rank
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	type cycle struct {
+		urls  []string
+		share float64
+	}
+	var found []cycle
+	for _, scc := range FindSCCs(backlinks) {
+		if len(scc) == 1 && !linksTo(backlinks, scc[0], scc[0]) {
+			continue
+		}
+		var sum float64
+		for _, url := range scc {
+			sum += rank[url]
+		}
+		if share := sum / total; share > minRankFraction {
+			found = append(found, cycle{scc, share})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].share > found[j].share })
+	cycles := make([][]string, len(found))
+	for i, c := range found {
+		cycles[i] = c.urls
+	}
+	return cycles
+}
+
+// BreakCycles cuts one link inside each cycle: the one carrying the least
+// rank, judged by ranking backlinks with default settings and outlink
+// counts taken from its links. It returns the pruned copy of backlinks and
+// the links it removed, in the order of cycles.
+func BreakCycles(backlinks map[string][]string, cycles [][]string) (map[string][]string, []EdgeRemoval) {
+	pruned, outlinks := inducedSubgraph(backlinks, collectURLs(backlinks, nil))
+	rank := ScoreMap(New().Calculate(pruned, outlinks))
+
+	var removed []EdgeRemoval
+	for _, cycle := range cycles {
+		member := make(map[string]bool, len(cycle))
+		for _, url := range cycle {
+			member[url] = true
+		}
+		var cut EdgeRemoval
+		best, found := 0.0, false
+		for _, target := range cycle {
+			for _, src := range pruned[target] {
+				if !member[src] {
+					continue
+				}
+				carried := rank[src] / float64(outlinks[src])
+				e := EdgeRemoval{Source: src, Target: target}
+				if !found || carried < best || (carried == best && (e.Source < cut.Source || (e.Source == cut.Source && e.Target < cut.Target))) {
+					cut, best, found = e, carried, true
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+		pruned[cut.Target] = removeFirst(pruned[cut.Target], cut.Source)
+		if len(pruned[cut.Target]) == 0 {
+			delete(pruned, cut.Target)
+		}
+		outlinks[cut.Source]--
+		removed = append(removed, cut)
+	}
+	return pruned, removed
+}
+
+func linksTo(backlinks map[string][]string, source, target string) bool {
+	for _, src := range backlinks[target] {
+		if src == source {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFirst(urls []string, url string) []string {
+	for i, u := range urls {
+		if u == url {
+			return append(urls[:i:i], urls[i+1:]...)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,63 @@
+package pagerank
+
+import (
+	"fmt"
+	"testing"
+)
+
+// trapGraph has three feeder pages linking into a mutual pair a <-> b that
+// links nowhere else, so the pair soaks up most of the rank.
+func trapGraph() map[string][]string {
+	return map[string][]string{
+		"a":      {"b", "feed-1", "feed-2"},
+		"b":      {"a", "feed-3"},
+		"feed-1": {"feed-3"},
+		"feed-2": {"feed-1"},
+		"feed-3": {"feed-2"},
+	}
+}
+
+func cycleRank(backlinks map[string][]string, cycle []string) float64 {
+	b, out := inducedSubgraph(backlinks, collectURLs(backlinks, nil))
+	rank := ScoreMap(New().Calculate(b, out))
+	var sum float64
+	for _, url := range cycle {
+		sum += rank[url]
+	}
+	return sum
+}
+
+func TestDetectRankCycles(t *testing.T) {
+	backlinks := trapGraph()
+	b, out := inducedSubgraph(backlinks, collectURLs(backlinks, nil))
+	results := New().Calculate(b, out)
+
+	cycles := DetectRankCycles(backlinks, results, 0.5)
+	if fmt.Sprint(cycles) != "[[a b]]" {
+		t.Fatalf("expected the a-b trap, got %v", cycles)
+	}
+	all := DetectRankCycles(backlinks, results, 0)
+	if len(all) != 2 {
+		t.Errorf("expected both cycles at threshold 0, got %v", all)
+	}
+}
+
+func TestBreakCyclesReducesCycleRank(t *testing.T) {
+	backlinks := trapGraph()
+	cycle := []string{"a", "b"}
+	before := cycleRank(backlinks, cycle)
+
+	pruned, removed := BreakCycles(backlinks, [][]string{cycle})
+	if len(removed) != 1 {
+		t.Fatalf("expected one removed edge, got %v", removed)
+	}
+	if e := removed[0]; !(e.Source == "a" && e.Target == "b") && !(e.Source == "b" && e.Target == "a") {
+		t.Fatalf("removed edge %v is not inside the cycle", e)
+	}
+	if linksTo(pruned, removed[0].Source, removed[0].Target) || !linksTo(backlinks, removed[0].Source, removed[0].Target) {
+		t.Fatal("BreakCycles should cut the edge in a copy only")
+	}
+	if after := cycleRank(pruned, cycle); after >= before {
+		t.Errorf("cycle rank %v did not drop below %v", after, before)
+	}
+}
@@ -0,0 +1,44 @@
+package pagerank
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// SetDiagnosticMode writes, during the first iteration, one line per link
+// with the rank it carries into its target:
+//
+//	source -> target:<TAB>contribution=X<TAB>source_rank=Y<TAB>outlinkCount=Z
+//
+// The contributions to a page sum to its first-iteration rank minus its
+// teleportation term, before any rank cap or inlink sampling. Two settings
+// break that sum: GaussSeidel updates pages from ranks already changed in
+// the same sweep and may renormalize it, while the lines are computed from
+// the starting ranks, and SetFullyCorrectFormulation adds each page's
+// share of the dangling pages' rank, which no line lists. Later iterations
+// are silent. nil turns it off.
+func (c *Calculator) SetDiagnosticMode(w io.Writer) *Calculator {
+	c.diagnostic = w
+	return c
+}
+
+// writeContributions writes the lines SetDiagnosticMode describes for the
+// step away from rank.
+func writeContributions[F rankFloat](w io.Writer, g *linkIndex, rank []F, damping float64) error {
+	bw := bufio.NewWriter(w)
+	for i, sources := range g.sources {
+		for k, src := range sources {
+			share := 1 / float64(g.out[src])
+			if g.weights != nil {
+				share = g.weights[i][k]
+			}
+			fmt.Fprintf(bw, "%s -> %s:\tcontribution=%g\tsource_rank=%g\toutlinkCount=%g\n",
+				g.urls[src], g.urls[i], damping*float64(rank[src])*share, float64(rank[src]), g.out[src])
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("pagerank: write diagnostics: %w", err)
+	}
+	return nil
+}
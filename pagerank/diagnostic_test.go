@@ -0,0 +1,53 @@
+package pagerank
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDiagnosticModeSumsToFirstIteration(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	var buf bytes.Buffer
+	calc := New().SetIterations(5).SetDiagnosticMode(&buf)
+	var first []float64
+	var urls []string
+	calc.observe = func(iteration int, u []string, rank []float64) {
+		if iteration == 1 {
+			urls, first = u, append([]float64(nil), rank...)
+		}
+	}
+	calc.Calculate(backlinks, outlinks)
+
+	inflow := make(map[string]float64)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	// One line per link over five iterations: the later ones are silent.
+	if len(lines) != EdgeCount(backlinks) {
+		t.Fatalf("got %d lines for %d links:\n%s", len(lines), EdgeCount(backlinks), buf.String())
+	}
+	for _, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 || !strings.HasSuffix(fields[0], ":") || !strings.HasPrefix(fields[1], "contribution=") {
+			t.Fatalf("malformed line %q", line)
+		}
+		link := strings.Split(strings.TrimSuffix(fields[0], ":"), " -> ")
+		x, err := strconv.ParseFloat(strings.TrimPrefix(fields[1], "contribution="), 64)
+		if err != nil || len(link) != 2 {
+			t.Fatalf("malformed line %q: %v", line, err)
+		}
+		if fields[3] != "outlinkCount="+strconv.Itoa(outlinks[link[0]]) {
+			t.Errorf("%q: want outlinkCount=%d", line, outlinks[link[0]])
+		}
+		inflow[link[1]] += x
+	}
+
+	teleport := (1 - calc.Damping()) / float64(len(urls))
+	for i, url := range urls {
+		if math.Abs(inflow[url]-(first[i]-teleport)) > 1e-12 {
+			t.Errorf("%s: contributions sum to %g, first iteration gave %g above teleport", url, inflow[url], first[i]-teleport)
+		}
+	}
+
+}
@@ -0,0 +1,76 @@
+package pagerank
+
+import "sort"
+
+// Edge is a link from Source to Target, as reported by DiffGraphs.
+type Edge struct {
+	Source string
+	Target string
+}
+
+// DiffGraphs compares the links of two snapshots. Duplicate links count
+// once. Both slices are sorted by source, then target.
+func DiffGraphs(before, after map[string][]string) (added, removed []Edge) {
+	old, cur := edgeSet(before), edgeSet(after)
+	for e := range cur {
+		if !old[e] {
+			added = append(added, Edge{Source: e.source, Target: e.target})
+		}
+	}
+	for e := range old {
+		if !cur[e] {
+			removed = append(removed, Edge{Source: e.source, Target: e.target})
+		}
+	}
+	sortEdges(added)
+	sortEdges(removed)
+	return added, removed
+}
+
+// ApplyGraphDiff returns a copy of base with removed dropped and added
+// appended. Adding a link that already exists does nothing; pages left
+// with no inlinks are dropped from the map.
+func ApplyGraphDiff(base map[string][]string, added, removed []Edge) map[string][]string {
+	drop := make(map[edge]bool, len(removed))
+	for _, e := range removed {
+		drop[edge{e.Source, e.Target}] = true
+	}
+	out := make(map[string][]string, len(base))
+	have := make(map[edge]bool)
+	for target, sources := range base {
+		for _, src := range sources {
+			e := edge{src, target}
+			if !drop[e] {
+				out[target] = append(out[target], src)
+				have[e] = true
+			}
+		}
+	}
+	for _, e := range added {
+		k := edge{e.Source, e.Target}
+		if !have[k] {
+			out[e.Target] = append(out[e.Target], e.Source)
+			have[k] = true
+		}
+	}
+	return out
+}
+
+func edgeSet(backlinks map[string][]string) map[edge]bool {
+	set := make(map[edge]bool)
+	for target, sources := range backlinks {
+		for _, src := range sources {
+			set[edge{src, target}] = true
+		}
+	}
+	return set
+}
+
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Source != edges[j].Source {
+			return edges[i].Source < edges[j].Source
+		}
+		return edges[i].Target < edges[j].Target
+	})
+}
@@ -0,0 +1,37 @@
+package pagerank
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffGraphsRoundTrip(t *testing.T) {
+	before, _ := sampleGraph()
+	after := map[string][]string{
+		"page-a": {"page-b", "page-d"},
+		"page-b": {"page-c"},
+		"page-c": {"page-a", "page-d"},
+		"page-d": {"page-b", "page-c"},
+		"page-e": {"page-a"},
+	}
+
+	added, removed := DiffGraphs(before, after)
+	if got := fmt.Sprint(added); got != "[{page-a page-e} {page-d page-a}]" {
+		t.Errorf("added = %s", got)
+	}
+	if got := fmt.Sprint(removed); got != "[{page-a page-d} {page-c page-a}]" {
+		t.Errorf("removed = %s", got)
+	}
+
+	forward := ApplyGraphDiff(before, added, removed)
+	if a, r := DiffGraphs(forward, after); len(a) != 0 || len(r) != 0 {
+		t.Errorf("applying the diff to before left %v added, %v removed", a, r)
+	}
+	back := ApplyGraphDiff(after, removed, added)
+	if a, r := DiffGraphs(back, before); len(a) != 0 || len(r) != 0 {
+		t.Errorf("applying the inverse to after left %v added, %v removed", a, r)
+	}
+	if len(before["page-a"]) != 2 {
+		t.Error("ApplyGraphDiff modified its input")
+	}
+}
@@ -0,0 +1,251 @@
+package pagerank
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// edgeList is what the file loaders produce: the links, plus any pages a
+// format can declare without links.
+type edgeList struct {
+	nodes []string
+	edges []edge
+}
+
+// LoadGraphFromCSV reads one "source,target" link per row. A first row of
+// exactly "source,target" is treated as a header and skipped.
+func LoadGraphFromCSV(r io.Reader) (map[string][]string, map[string]int, error) {
+	list, err := readEdgesDelimited(r, ',')
+	if err != nil {
+		return nil, nil, err
+	}
+	g := NewGraph()
+	g.addEdgeList(list)
+	backlinks, outlinks := g.Build()
+	return backlinks, outlinks, nil
+}
+
+// LoadGraphFromAdjacencyMatrix reads an N×N matrix of whitespace-separated
+// 0s and 1s, one row per line, where a 1 in row i, column j is a link from
+// urls[i] to urls[j]. Blank lines are skipped. The matrix must be square and
+// match len(urls).
+func LoadGraphFromAdjacencyMatrix(r io.Reader, urls []string) (map[string][]string, map[string]int, error) {
+	g := NewGraph()
+	for _, url := range urls {
+		g.AddNode(url)
+	}
+	sc := bufio.NewScanner(r)
+	row := 0
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != len(urls) {
+			return nil, nil, fmt.Errorf("pagerank: adjacency matrix row %d has %d columns, want %d", row+1, len(fields), len(urls))
+		}
+		if row >= len(urls) {
+			return nil, nil, fmt.Errorf("pagerank: adjacency matrix has more than %d rows", len(urls))
+		}
+		for j, f := range fields {
+			switch f {
+			case "0":
+			case "1":
+				g.AddEdge(urls[row], urls[j])
+			default:
+				return nil, nil, fmt.Errorf("pagerank: adjacency matrix row %d column %d: %q is not 0 or 1", row+1, j+1, f)
+			}
+		}
+		row++
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, fmt.Errorf("pagerank: read adjacency matrix: %w", err)
+	}
+	if row != len(urls) {
+		return nil, nil, fmt.Errorf("pagerank: adjacency matrix has %d rows, want %d", row, len(urls))
+	}
+	backlinks, outlinks := g.Build()
+	return backlinks, outlinks, nil
+}
+
+// AddEdgesFromFile loads links from path, picking the format from its
+// extension: .csv, .tsv, .json (an array of {"source", "target"}
+// objects), .gexf or .dot.
+func (g *Graph) AddEdgesFromFile(path string) error {
+	var read func(io.Reader) (edgeList, error)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		read = func(r io.Reader) (edgeList, error) { return readEdgesDelimited(r, ',') }
+	case ".tsv":
+		read = func(r io.Reader) (edgeList, error) { return readEdgesDelimited(r, '\t') }
+	case ".json":
+		read = readEdgesJSON
+	case ".gexf":
+		read = readEdgesGEXF
+	case ".dot", ".gv":
+		read = readEdgesDOT
+	default:
+		return fmt.Errorf("pagerank: unknown graph file extension %q", ext)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("pagerank: open graph file: %w", err)
+	}
+	defer f.Close()
+	list, err := read(f)
+	if err != nil {
+		return fmt.Errorf("pagerank: read %s: %w", path, err)
+	}
+	g.addEdgeList(list)
+	return nil
+}
+
+func (g *Graph) addEdgeList(list edgeList) {
+	for _, url := range list.nodes {
+		g.AddNode(url)
+	}
+	for _, e := range list.edges {
+		g.AddEdge(e.source, e.target)
+	}
+}
+
+func readEdgesDelimited(r io.Reader, comma rune) (edgeList, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+	var list edgeList
+	for row := 0; ; row++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return list, nil
+		}
+		if err != nil {
+			return edgeList{}, err
+		}
+		if row == 0 && rec[0] == "source" && rec[1] == "target" {
+			continue
+		}
+		list.edges = append(list.edges, edge{source: rec[0], target: rec[1]})
+	}
+}
+
+func readEdgesJSON(r io.Reader) (edgeList, error) {
+	var rows []struct {
+		Source string `json:"source"`
+		Target string `json:"target"`
+	}
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return edgeList{}, err
+	}
+	var list edgeList
+	for i, row := range rows {
+		if row.Source == "" || row.Target == "" {
+			return edgeList{}, fmt.Errorf("edge %d is missing its source or target", i)
+		}
+		list.edges = append(list.edges, edge{source: row.Source, target: row.Target})
+	}
+	return list, nil
+}
+
+// readEdgesGEXF names pages by their node label, falling back to the id.
+func readEdgesGEXF(r io.Reader) (edgeList, error) {
+	var doc struct {
+		Nodes []struct {
+			ID    string `xml:"id,attr"`
+			Label string `xml:"label,attr"`
+		} `xml:"graph>nodes>node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+			Target string `xml:"target,attr"`
+		} `xml:"graph>edges>edge"`
+	}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return edgeList{}, err
+	}
+	name := make(map[string]string, len(doc.Nodes))
+	var list edgeList
+	for _, n := range doc.Nodes {
+		name[n.ID] = n.ID
+		if n.Label != "" {
+			name[n.ID] = n.Label
+		}
+		list.nodes = append(list.nodes, name[n.ID])
+	}
+	lookup := func(id string) string {
+		if n, ok := name[id]; ok {
+			return n
+		}
+		return id
+	}
+	for _, e := range doc.Edges {
+		list.edges = append(list.edges, edge{source: lookup(e.Source), target: lookup(e.Target)})
+	}
+	return list, nil
+}
+
+// readEdgesDOT understands the subset of DOT that graph exporters
+// produce: one node or edge statement per line or per ';', with optional
+// attribute lists, quoted or bare IDs and chains like a -> b -> c.
+func readEdgesDOT(r io.Reader) (edgeList, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return edgeList{}, err
+	}
+	var list edgeList
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, stmt := range strings.Split(line, ";") {
+			stmt = strings.TrimSpace(stmt)
+			if i := strings.IndexByte(stmt, '['); i >= 0 {
+				stmt = strings.TrimSpace(stmt[:i])
+			}
+			if stmt == "" || strings.ContainsAny(stmt, "{}=") {
+				continue
+			}
+			parts := strings.Split(stmt, "->")
+			ids := make([]string, len(parts))
+			for i, p := range parts {
+				id, err := dotID(strings.TrimSpace(p))
+				if err != nil {
+					return edgeList{}, err
+				}
+				ids[i] = id
+			}
+			if len(ids) == 1 {
+				list.nodes = append(list.nodes, ids[0])
+				continue
+			}
+			for i := 1; i < len(ids); i++ {
+				list.edges = append(list.edges, edge{source: ids[i-1], target: ids[i]})
+			}
+		}
+	}
+	return list, nil
+}
+
+func dotID(s string) (string, error) {
+	if strings.HasPrefix(s, `"`) {
+		id, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("bad DOT id %s: %w", s, err)
+		}
+		return id, nil
+	}
+	if s == "" || strings.ContainsAny(s, " \t\"") {
+		return "", fmt.Errorf("bad DOT id %q", s)
+	}
+	return s, nil
+}
@@ -0,0 +1,123 @@
+package pagerank
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestAddEdgesFromFileFormats(t *testing.T) {
+	files := map[string]string{
+		"graph.csv": "source,target\na,b\nb,c\nc,a\n",
+		"graph.tsv": "a\tb\nb\tc\nc\ta\n",
+		"graph.json": `[{"source": "a", "target": "b"}, {"source": "b", "target": "c"},
+			{"source": "c", "target": "a"}]`,
+		"graph.gexf": `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <graph defaultedgetype="directed">
+    <nodes><node id="0" label="a"/><node id="1" label="b"/><node id="2" label="c"/></nodes>
+    <edges><edge source="0" target="1"/><edge source="1" target="2"/><edge source="2" target="0"/></edges>
+  </graph>
+</gexf>`,
+		"graph.dot": "digraph G {\n  // cycle\n  \"a\" -> \"b\" [weight=1];\n  b -> c; c -> a\n}\n",
+	}
+	dir := t.TempDir()
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		g := NewGraph()
+		if err := g.AddEdgesFromFile(path); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		backlinks, outlinks := g.Build()
+		if g.EdgeCount() != 3 || len(outlinks) != 3 {
+			t.Errorf("%s: got %d edges, %d nodes", name, g.EdgeCount(), len(outlinks))
+		}
+		if fmt.Sprint(backlinks["a"]) != "[c]" || fmt.Sprint(backlinks["c"]) != "[b]" {
+			t.Errorf("%s: unexpected backlinks %v", name, backlinks)
+		}
+	}
+}
+
+func TestAddEdgesFromFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "graph.txt")
+	os.WriteFile(bad, []byte("a,b\n"), 0o644)
+	if err := NewGraph().AddEdgesFromFile(bad); err == nil || !strings.Contains(err.Error(), "extension") {
+		t.Errorf("expected an unknown extension error, got %v", err)
+	}
+	if err := NewGraph().AddEdgesFromFile(filepath.Join(dir, "missing.csv")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	short := filepath.Join(dir, "short.csv")
+	os.WriteFile(short, []byte("a,b\nc\n"), 0o644)
+	if err := NewGraph().AddEdgesFromFile(short); err == nil {
+		t.Error("expected an error for a row with one field")
+	}
+}
+
+func TestLoadGraphFromCSV(t *testing.T) {
+	backlinks, outlinks, err := LoadGraphFromCSV(strings.NewReader("a,b\na,c\na,b\n"))
+	if err != nil {
+		t.Fatalf("LoadGraphFromCSV: %v", err)
+	}
+	if outlinks["a"] != 2 || len(backlinks["b"]) != 1 {
+		t.Errorf("unexpected graph %v %v", backlinks, outlinks)
+	}
+}
+
+func TestLoadGraphFromAdjacencyMatrix(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	urls := collectURLs(backlinks, outlinks)
+	var matrix strings.Builder
+	for _, src := range urls {
+		for j, target := range urls {
+			cell := "0"
+			for _, s := range backlinks[target] {
+				if s == src {
+					cell = "1"
+				}
+			}
+			if j > 0 {
+				matrix.WriteString(" ")
+			}
+			matrix.WriteString(cell)
+		}
+		matrix.WriteString("\n")
+	}
+
+	gotBack, gotOut, err := LoadGraphFromAdjacencyMatrix(strings.NewReader(matrix.String()), urls)
+	if err != nil {
+		t.Fatalf("LoadGraphFromAdjacencyMatrix: %v", err)
+	}
+	if !reflect.DeepEqual(gotOut, outlinks) {
+		t.Errorf("outlinks = %v, want %v", gotOut, outlinks)
+	}
+	for _, url := range urls {
+		got := append([]string(nil), gotBack[url]...)
+		want := append([]string(nil), backlinks[url]...)
+		sort.Strings(got)
+		sort.Strings(want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: backlinks %v, want %v", url, got, want)
+		}
+	}
+
+	for name, doc := range map[string]string{
+		"not square":   "0 1\n1 0\n",
+		"ragged":       "0 1 0 0\n1 0\n0 0 0 0\n0 0 0 0\n",
+		"too many":     strings.Repeat("0 0 0 0\n", 5),
+		"not a bit":    "0 2 0 0\n" + strings.Repeat("0 0 0 0\n", 3),
+		"empty matrix": "",
+	} {
+		if _, _, err := LoadGraphFromAdjacencyMatrix(strings.NewReader(doc), urls); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
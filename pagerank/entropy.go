@@ -0,0 +1,50 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+)
+
+// RankEntropy is the Shannon entropy, in bits, of the ranks taken as a
+// probability distribution: log2(N) when every page ranks the same and 0
+// when one page holds all the rank. Ranks are normalized to sum to 1
+// first, and non-positive ranks are skipped.
+func RankEntropy(results []Result) float64 {
+	ranks := make([]float64, len(results))
+	for i, r := range results {
+		ranks[i] = r.Rank
+	}
+	return entropy(ranks)
+}
+
+// IterativeEntropy runs the calculation and returns RankEntropy of the
+// rank vector after each iteration, showing how fast rank concentrates.
+// It returns nil if the graph is rejected by a configured limit.
+func (c *Calculator) IterativeEntropy(backlinks map[string][]string, outlinksCount map[string]int) []float64 {
+	var out []float64
+	calc := c.Clone()
+	calc.observe = func(_ int, _ []string, rank []float64) {
+		out = append(out, entropy(rank))
+	}
+	if _, err := calc.CalculateContext(context.Background(), backlinks, outlinksCount); err != nil {
+		return nil
+	}
+	return out
+}
+
+func entropy(ranks []float64) float64 {
+	var total float64
+	for _, r := range ranks {
+		if r > 0 {
+			total += r
+		}
+	}
+	var h float64
+	for _, r := range ranks {
+		if r > 0 {
+			p := r / total
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
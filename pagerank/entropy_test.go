@@ -0,0 +1,38 @@
+package pagerank
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRankEntropyBounds(t *testing.T) {
+	equal := []Result{{"a", 0.25}, {"b", 0.25}, {"c", 0.25}, {"d", 0.25}}
+	if got := RankEntropy(equal); math.Abs(got-2) > 1e-12 {
+		t.Errorf("equal ranks: entropy %v, want log2(4) = 2", got)
+	}
+	single := []Result{{"a", 1}, {"b", 0}, {"c", 0}}
+	if got := RankEntropy(single); got != 0 {
+		t.Errorf("one page with all rank: entropy %v, want 0", got)
+	}
+	// A cycle ranks every page equally.
+	backlinks := map[string][]string{"a": {"c"}, "b": {"a"}, "c": {"b"}}
+	outlinks := map[string]int{"a": 1, "b": 1, "c": 1}
+	if got := RankEntropy(New().Calculate(backlinks, outlinks)); math.Abs(got-math.Log2(3)) > 1e-12 {
+		t.Errorf("cycle: entropy %v, want log2(3)", got)
+	}
+}
+
+func TestIterativeEntropy(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(200, 3, 5)
+	calc := New().SetIterations(20)
+	trace := calc.IterativeEntropy(backlinks, outlinks)
+	if len(trace) != 20 {
+		t.Fatalf("expected 20 entries, got %d", len(trace))
+	}
+	if max := math.Log2(200); trace[0] >= max {
+		t.Errorf("first iteration entropy %v should fall below the uniform %v", trace[0], max)
+	}
+	if final := RankEntropy(calc.Calculate(backlinks, outlinks)); math.Abs(trace[19]-final) > 1e-12 {
+		t.Errorf("last entry %v, entropy of results %v", trace[19], final)
+	}
+}
@@ -0,0 +1,217 @@
+package pagerank
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ExportDOT writes the graph in Graphviz DOT format with each page's rank
+// as a node attribute and its width scaled by rank. If layout is non-nil,
+// pages in it get a pinned pos attribute for neato -n.
+func ExportDOT(w io.Writer, backlinks map[string][]string, results []Result, layout map[string]NodePosition) error {
+	bw := bufio.NewWriter(w)
+	rank := ScoreMap(results)
+	urls := exportURLs(backlinks, results)
+	size := layoutSizes(urls, results)
+
+	fmt.Fprintln(bw, "digraph pagerank {")
+	for i, url := range urls {
+		fmt.Fprintf(bw, "  %s [rank=%g, width=%.3f", dotQuote(url), rank[url], 0.5+size[i])
+		if p, ok := layout[url]; ok {
+			fmt.Fprintf(bw, `, pos="%g,%g!"`, p.X, p.Y)
+		}
+		fmt.Fprintln(bw, "];")
+	}
+	for _, target := range collectURLs(backlinks, nil) {
+		for _, src := range backlinks[target] {
+			fmt.Fprintf(bw, "  %s -> %s;\n", dotQuote(src), dotQuote(target))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+type gexfDoc struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	VizNS   string    `xml:"xmlns:viz,attr"`
+	Version string    `xml:"version,attr"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	EdgeType   string        `xml:"defaultedgetype,attr"`
+	Attributes gexfAttrClass `xml:"attributes"`
+	Nodes      []gexfNode    `xml:"nodes>node"`
+	Edges      []gexfEdge    `xml:"edges>edge"`
+}
+
+type gexfAttrClass struct {
+	Class string     `xml:"class,attr"`
+	Attrs []gexfAttr `xml:"attribute"`
+}
+
+type gexfAttr struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID       string        `xml:"id,attr"`
+	Label    string        `xml:"label,attr"`
+	Values   []gexfValue   `xml:"attvalues>attvalue"`
+	Size     *gexfSize     `xml:"viz:size"`
+	Position *gexfPosition `xml:"viz:position"`
+}
+
+type gexfValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type gexfSize struct {
+	Value float64 `xml:"value,attr"`
+}
+
+type gexfPosition struct {
+	X float64 `xml:"x,attr"`
+	Y float64 `xml:"y,attr"`
+	Z float64 `xml:"z,attr"`
+}
+
+type gexfEdge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+// ExportGEXF writes the graph as GEXF 1.3 for Gephi, with rank as a node
+// attribute and visual size. If layout is non-nil, pages in it get a
+// viz:position.
+func ExportGEXF(w io.Writer, backlinks map[string][]string, results []Result, layout map[string]NodePosition) error {
+	rank := ScoreMap(results)
+	urls := exportURLs(backlinks, results)
+	size := layoutSizes(urls, results)
+
+	doc := gexfDoc{
+		XMLNS:   "http://gexf.net/1.3",
+		VizNS:   "http://gexf.net/1.3/viz",
+		Version: "1.3",
+		Graph: gexfGraph{
+			EdgeType: "directed",
+			Attributes: gexfAttrClass{
+				Class: "node",
+				Attrs: []gexfAttr{{ID: "rank", Title: "pagerank", Type: "double"}},
+			},
+		},
+	}
+
+	ids := make(map[string]string, len(urls))
+	for i, url := range urls {
+		ids[url] = fmt.Sprintf("n%d", i)
+		node := gexfNode{
+			ID:     ids[url],
+			Label:  url,
+			Values: []gexfValue{{For: "rank", Value: fmt.Sprintf("%g", rank[url])}},
+			Size:   &gexfSize{Value: 1 + 9*size[i]},
+		}
+		if p, ok := layout[url]; ok {
+			node.Position = &gexfPosition{X: p.X, Y: p.Y}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for _, target := range collectURLs(backlinks, nil) {
+		for _, src := range backlinks[target] {
+			doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+				ID:     fmt.Sprintf("e%d", len(doc.Graph.Edges)),
+				Source: ids[src],
+				Target: ids[target],
+			})
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type cytoscapeNode struct {
+	Data struct {
+		ID    string  `json:"id"`
+		Label string  `json:"label"`
+		Rank  float64 `json:"rank"`
+	} `json:"data"`
+}
+
+type cytoscapeEdge struct {
+	Data struct {
+		Source string `json:"source"`
+		Target string `json:"target"`
+	} `json:"data"`
+}
+
+// ExportCytoscapeJSON writes the graph in Cytoscape.js's elements format.
+// Nodes are identified and labelled by URL, and data.rank is the page's
+// rank divided by the highest, in [0, 1], for use as a size.
+func ExportCytoscapeJSON(w io.Writer, backlinks map[string][]string, results []Result) error {
+	urls := exportURLs(backlinks, results)
+	size := layoutSizes(urls, results)
+
+	var doc struct {
+		Elements struct {
+			Nodes []cytoscapeNode `json:"nodes"`
+			Edges []cytoscapeEdge `json:"edges"`
+		} `json:"elements"`
+	}
+	doc.Elements.Nodes = make([]cytoscapeNode, len(urls))
+	for i, url := range urls {
+		n := &doc.Elements.Nodes[i]
+		n.Data.ID, n.Data.Label, n.Data.Rank = url, url, size[i]
+	}
+	doc.Elements.Edges = []cytoscapeEdge{}
+	for _, target := range collectURLs(backlinks, nil) {
+		for _, src := range backlinks[target] {
+			var e cytoscapeEdge
+			e.Data.Source, e.Data.Target = src, target
+			doc.Elements.Edges = append(doc.Elements.Edges, e)
+		}
+	}
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		return fmt.Errorf("pagerank: encode cytoscape elements: %w", err)
+	}
+	return nil
+}
+
+// exportURLs lists every page in the links or the results, sorted.
+func exportURLs(backlinks map[string][]string, results []Result) []string {
+	urls := collectURLs(backlinks, nil)
+	seen := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		seen[url] = true
+	}
+	for _, r := range results {
+		if !seen[r.URL] {
+			seen[r.URL] = true
+			urls = append(urls, r.URL)
+		}
+	}
+	sort.Strings(urls)
+	return urls
+}
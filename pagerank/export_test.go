@@ -0,0 +1,106 @@
+package pagerank
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestExportDOTRoundTrip(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	results := New().Calculate(backlinks, outlinks)
+	layout := ComputeForceDirectedLayout(backlinks, results, 20, 1)
+
+	var buf bytes.Buffer
+	if err := ExportDOT(&buf, backlinks, results, layout); err != nil {
+		t.Fatalf("ExportDOT: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "digraph pagerank {") || !strings.Contains(out, `pos="`) || !strings.Contains(out, "rank=") {
+		t.Fatalf("unexpected DOT output:\n%s", out)
+	}
+	list, err := readEdgesDOT(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("readEdgesDOT: %v", err)
+	}
+	if len(list.edges) != EdgeCount(backlinks) || len(list.nodes) != 4 {
+		t.Errorf("read back %d edges and %d nodes", len(list.edges), len(list.nodes))
+	}
+}
+
+func TestExportGEXFRoundTrip(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	results := New().Calculate(backlinks, outlinks)
+	layout := ComputeForceDirectedLayout(backlinks, results, 20, 1)
+
+	var buf bytes.Buffer
+	if err := ExportGEXF(&buf, backlinks, results, layout); err != nil {
+		t.Fatalf("ExportGEXF: %v", err)
+	}
+	var doc struct {
+		Nodes []struct {
+			Label string `xml:"label,attr"`
+			Pos   struct {
+				X string `xml:"x,attr"`
+			} `xml:"position"`
+		} `xml:"graph>nodes>node"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	if len(doc.Nodes) != 4 || doc.Nodes[0].Pos.X == "" {
+		t.Errorf("unexpected nodes %+v", doc.Nodes)
+	}
+
+	list, err := readEdgesGEXF(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("readEdgesGEXF: %v", err)
+	}
+	if len(list.edges) != EdgeCount(backlinks) || list.nodes[0] != "page-a" {
+		t.Errorf("read back %d edges, nodes %v", len(list.edges), list.nodes)
+	}
+}
+
+func TestExportCytoscapeJSON(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	results := New().Calculate(backlinks, outlinks)
+
+	var buf bytes.Buffer
+	if err := ExportCytoscapeJSON(&buf, backlinks, results); err != nil {
+		t.Fatalf("ExportCytoscapeJSON: %v", err)
+	}
+	var doc struct {
+		Elements struct {
+			Nodes []struct {
+				Data struct {
+					ID    string  `json:"id"`
+					Label string  `json:"label"`
+					Rank  float64 `json:"rank"`
+				} `json:"data"`
+			} `json:"nodes"`
+			Edges []struct {
+				Data struct {
+					Source string `json:"source"`
+					Target string `json:"target"`
+				} `json:"data"`
+			} `json:"edges"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.Bytes())
+	}
+	if len(doc.Elements.Nodes) != 4 || len(doc.Elements.Edges) != EdgeCount(backlinks) {
+		t.Fatalf("got %d nodes and %d edges, want 4 and %d", len(doc.Elements.Nodes), len(doc.Elements.Edges), EdgeCount(backlinks))
+	}
+	top := results[0].URL
+	for _, n := range doc.Elements.Nodes {
+		if n.Data.ID != n.Data.Label || n.Data.Rank < 0 || n.Data.Rank > 1 {
+			t.Errorf("unexpected node %+v", n.Data)
+		}
+		if (n.Data.ID == top) != (n.Data.Rank == 1) {
+			t.Errorf("%s has normalized rank %g; only %s should have 1", n.Data.ID, n.Data.Rank, top)
+		}
+	}
+}
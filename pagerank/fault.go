@@ -0,0 +1,50 @@
+package pagerank
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInjectedFault = errors.New("pagerank: injected fault")
+
+// FaultInjector makes a calculation fail on purpose so callers can test
+// their error and recovery paths. Counts are completed iterations; zero
+// disables that fault. When both are due on the same iteration the panic
+// wins.
+type FaultInjector struct {
+	// FailAfter stops the calculation with Err (ErrInjectedFault if nil)
+	// once this many iterations have run.
+	FailAfter int
+	Err       error
+
+	// PanicAfter panics with PanicValue (a descriptive string if nil) once
+	// this many iterations have run.
+	PanicAfter int
+	PanicValue any
+}
+
+// WithFaultInjector arms faults for every later calculation; nil disarms
+// it. Failures surface as errors from CalculateContext and as empty
+// results from Calculate.
+func (c *Calculator) WithFaultInjector(faults *FaultInjector) *Calculator {
+	c.faults = faults
+	return c
+}
+
+// check is called after each iteration.
+func (f *FaultInjector) check(iteration int) error {
+	if f.PanicAfter > 0 && iteration == f.PanicAfter {
+		if f.PanicValue != nil {
+			panic(f.PanicValue)
+		}
+		panic(fmt.Sprintf("pagerank: injected panic after iteration %d", iteration))
+	}
+	if f.FailAfter > 0 && iteration == f.FailAfter {
+		err := f.Err
+		if err == nil {
+			err = ErrInjectedFault
+		}
+		return fmt.Errorf("after iteration %d: %w", iteration, err)
+	}
+	return nil
+}
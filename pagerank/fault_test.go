@@ -0,0 +1,58 @@
+package pagerank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFaultInjectorFailsAtIteration(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calc := New().WithFaultInjector(&FaultInjector{FailAfter: 7})
+	ran := 0
+	calc.observe = func(iteration int, _ []string, _ []float64) { ran = iteration }
+
+	_, err := calc.CalculateContext(context.Background(), backlinks, outlinks)
+	if !errors.Is(err, ErrInjectedFault) {
+		t.Fatalf("expected ErrInjectedFault, got %v", err)
+	}
+	if ran != 7 {
+		t.Errorf("fault fired after iteration %d, want 7", ran)
+	}
+	if got := calc.Calculate(backlinks, outlinks); len(got) != 0 {
+		t.Errorf("Calculate returned %d results despite the fault", len(got))
+	}
+
+	custom := errors.New("disk on fire")
+	calc.WithFaultInjector(&FaultInjector{FailAfter: 1, Err: custom})
+	if _, err := calc.CalculateContext(context.Background(), backlinks, outlinks); !errors.Is(err, custom) {
+		t.Errorf("expected the custom error, got %v", err)
+	}
+
+	calc.WithFaultInjector(nil)
+	if got := calc.Calculate(backlinks, outlinks); len(got) != 4 {
+		t.Errorf("disarmed calculator returned %d results", len(got))
+	}
+}
+
+func TestFaultInjectorPanics(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calc := New().WithFaultInjector(&FaultInjector{PanicAfter: 3, FailAfter: 3})
+	ran := 0
+	calc.observe = func(iteration int, _ []string, _ []float64) { ran = iteration }
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected a panic")
+		}
+		if ran != 3 {
+			t.Errorf("panic fired after iteration %d, want 3", ran)
+		}
+		if fmt.Sprint(r) != "pagerank: injected panic after iteration 3" {
+			t.Errorf("unexpected panic value %v", r)
+		}
+	}()
+	calc.Calculate(backlinks, outlinks)
+}
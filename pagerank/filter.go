@@ -0,0 +1,56 @@
+package pagerank
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// FilterByURLPrefix keeps the results whose URL starts with prefix.
+func FilterByURLPrefix(results []Result, prefix string) []Result {
+	return filterResults(results, func(u string) bool { return strings.HasPrefix(u, prefix) })
+}
+
+// FilterByGlob keeps the results matching pattern, using path.Match
+// syntax. A pattern without a '/' is matched against the URL's host, so
+// "*.example.com" selects every subdomain; otherwise it is matched against
+// the whole URL.
+func FilterByGlob(results []Result, pattern string) ([]Result, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("pagerank: invalid glob %q: %w", pattern, err)
+	}
+	hostOnly := !strings.Contains(pattern, "/")
+	return filterResults(results, func(u string) bool {
+		subject := u
+		if hostOnly {
+			parsed, err := url.Parse(u)
+			if err != nil {
+				return false
+			}
+			subject = parsed.Hostname()
+		}
+		ok, _ := path.Match(pattern, subject)
+		return ok
+	}), nil
+}
+
+// FilterByRegex keeps the results whose URL contains a match for pattern.
+func FilterByRegex(results []Result, pattern string) ([]Result, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: invalid regex %q: %w", pattern, err)
+	}
+	return filterResults(results, re.MatchString), nil
+}
+
+func filterResults(results []Result, keep func(url string) bool) []Result {
+	out := make([]Result, 0, len(results))
+	for _, r := range results {
+		if keep(r.URL) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
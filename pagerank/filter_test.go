@@ -0,0 +1,64 @@
+package pagerank
+
+import (
+	"fmt"
+	"testing"
+)
+
+var filterResultsFixture = []Result{
+	{URL: "https://example.com/", Rank: 0.4},
+	{URL: "https://blog.example.com/posts/42", Rank: 0.3},
+	{URL: "https://a.b.example.com/about", Rank: 0.2},
+	{URL: "https://other.org/page", Rank: 0.1},
+}
+
+func urlsOf(results []Result) string {
+	urls := make([]string, len(results))
+	for i, r := range results {
+		urls[i] = r.URL
+	}
+	return fmt.Sprint(urls)
+}
+
+func TestFilterByGlob(t *testing.T) {
+	got, err := FilterByGlob(filterResultsFixture, "*.example.com")
+	if err != nil {
+		t.Fatalf("FilterByGlob: %v", err)
+	}
+	want := "[https://blog.example.com/posts/42 https://a.b.example.com/about]"
+	if urlsOf(got) != want {
+		t.Errorf("got %s, want %s", urlsOf(got), want)
+	}
+
+	got, err = FilterByGlob(filterResultsFixture, "https://*/posts/??")
+	if err != nil {
+		t.Fatalf("FilterByGlob: %v", err)
+	}
+	if urlsOf(got) != "[https://blog.example.com/posts/42]" {
+		t.Errorf("full-URL glob got %s", urlsOf(got))
+	}
+
+	if _, err := FilterByGlob(filterResultsFixture, "[a-"); err == nil {
+		t.Error("expected an error for an invalid glob")
+	}
+}
+
+func TestFilterByRegex(t *testing.T) {
+	got, err := FilterByRegex(filterResultsFixture, `/\d+`)
+	if err != nil {
+		t.Fatalf("FilterByRegex: %v", err)
+	}
+	if urlsOf(got) != "[https://blog.example.com/posts/42]" {
+		t.Errorf("got %s", urlsOf(got))
+	}
+	if _, err := FilterByRegex(filterResultsFixture, `(\d+`); err == nil {
+		t.Error("expected an error for an invalid regex")
+	}
+}
+
+func TestFilterByURLPrefix(t *testing.T) {
+	got := FilterByURLPrefix(filterResultsFixture, "https://example.com")
+	if urlsOf(got) != "[https://example.com/]" {
+		t.Errorf("got %s", urlsOf(got))
+	}
+}
@@ -0,0 +1,159 @@
+package pagerank
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// String summarizes the calculator's configuration. Optional settings are
+// listed only when they are in effect.
+func (c *Calculator) String() string {
+	parts := []string{
+		fmt.Sprintf("damping: %g", c.damping),
+		fmt.Sprintf("iterations: %d", c.iterations),
+	}
+	if c.adaptive != nil {
+		parts = append(parts, fmt.Sprintf("adaptiveDamping: [%g, %g]", c.adaptive.min, c.adaptive.max))
+	}
+	if c.tolerance > 0 {
+		parts = append(parts, fmt.Sprintf("tolerance: %g", c.tolerance))
+	}
+	if c.maxInlinks > 0 {
+		parts = append(parts, fmt.Sprintf("maxInlinksPerNode: %d", c.maxInlinks))
+	}
+	if c.maxRankCap > 0 {
+		parts = append(parts, fmt.Sprintf("maxRankCap: %g", c.maxRankCap))
+	}
+	if len(c.restart) > 0 {
+		parts = append(parts, fmt.Sprintf("restart: %d seeds", len(c.restart)))
+	}
+	if len(c.teleport) > 0 {
+		parts = append(parts, fmt.Sprintf("teleportMatrix: %d rows", len(c.teleport)))
+	}
+	if c.maxNodes > 0 {
+		parts = append(parts, fmt.Sprintf("maxNodes: %d", c.maxNodes))
+	}
+	if c.maxEdges > 0 {
+		parts = append(parts, fmt.Sprintf("maxEdges: %d", c.maxEdges))
+	}
+	if c.maxMemory > 0 {
+		parts = append(parts, fmt.Sprintf("maxMemoryBytes: %d", c.maxMemory))
+	}
+	if c.faults != nil {
+		parts = append(parts, "faultInjector: set")
+	}
+	if c.normalizer != nil {
+		parts = append(parts, "outlinkNormalizer: set")
+	}
+	if c.classifier != nil {
+		parts = append(parts, "linkClassifier: set")
+	}
+	if len(c.absorbing) > 0 {
+		parts = append(parts, fmt.Sprintf("absorbing: %d pages", len(c.absorbing)))
+	}
+	if c.undirected {
+		parts = append(parts, "undirected: true")
+	}
+	if c.rankLog != nil {
+		parts = append(parts, "rankLogger: set")
+	}
+	if c.diagnostic != nil {
+		parts = append(parts, "diagnostics: set")
+	}
+	if len(c.spam) > 0 {
+		parts = append(parts, fmt.Sprintf("spamScores: %d pages", len(c.spam)))
+	}
+	if len(c.focus) > 0 {
+		parts = append(parts, fmt.Sprintf("focus: %d pages", len(c.focus)))
+	}
+	if c.historyEnabled {
+		parts = append(parts, "history: on")
+	}
+	if c.precision != Float64 {
+		parts = append(parts, "precision: "+c.precision.String())
+	}
+	if c.method != Jacobi {
+		parts = append(parts, "method: "+c.method.String())
+	}
+	if c.fullyCorrect {
+		parts = append(parts, "fullyCorrect: true")
+	}
+	return "Calculator{" + strings.Join(parts, ", ") + "}"
+}
+
+// GoString shows every field, for %#v.
+func (c *Calculator) GoString() string {
+	rng, faults, normalizer, classifier, rankLog, adaptive, diagnostic := "nil", "nil", "nil", "nil", "nil", "nil", "nil"
+	if c.adaptive != nil {
+		adaptive = fmt.Sprintf("[%g, %g]", c.adaptive.min, c.adaptive.max)
+	}
+	if c.rng != nil {
+		rng = "set"
+	}
+	if c.faults != nil {
+		faults = "set"
+	}
+	if c.normalizer != nil {
+		normalizer = "set"
+	}
+	if c.classifier != nil {
+		classifier = "set"
+	}
+	if c.rankLog != nil {
+		rankLog = "set"
+	}
+	if c.diagnostic != nil {
+		diagnostic = "set"
+	}
+	return fmt.Sprintf("&pagerank.Calculator{damping: %g, adaptive: %s, iterations: %d, tolerance: %g, maxInlinks: %d, rng: %s, maxRankCap: %g, restart: %s, teleport: %d rows, maxNodes: %d, maxEdges: %d, maxMemory: %d, faults: %s, precision: %s, method: %s, fullyCorrect: %t, normalizer: %s, classifier: %s, spam: %s, rankLogger: %s, diagnostics: %s, undirected: %t, absorbing: %d pages, focus: %d pages, history: %t}",
+		c.damping, adaptive, c.iterations, c.tolerance, c.maxInlinks, rng, c.maxRankCap, sortedFloatMap(c.restart), len(c.teleport), c.maxNodes, c.maxEdges, c.maxMemory, faults, c.precision, c.method, c.fullyCorrect, normalizer, classifier, sortedFloatMap(c.spam), rankLog, diagnostic, c.undirected, len(c.absorbing), len(c.focus), c.historyEnabled)
+}
+
+func sortedFloatMap(m map[string]float64) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%q: %g", k, m[k])
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// Format implements fmt.Formatter:
+//
+//	%v, %s  url (rank)
+//	%+v     URL=url Rank=rank
+//	%#v     pagerank.Result{URL:"url", Rank:rank}
+//	%q      "url" rank
+//	%e, %E  url (rank in scientific notation)
+//
+// A precision, as in %.4v, applies to the rank.
+func (r Result) Format(f fmt.State, verb rune) {
+	rank := "%g"
+	if p, ok := f.Precision(); ok {
+		rank = "%." + strconv.Itoa(p) + "g"
+		if verb == 'e' || verb == 'E' {
+			rank = "%." + strconv.Itoa(p) + string(verb)
+		}
+	} else if verb == 'e' || verb == 'E' {
+		rank = "%" + string(verb)
+	}
+
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprintf(f, "pagerank.Result{URL:%q, Rank:"+rank+"}", r.URL, r.Rank)
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprintf(f, "URL=%s Rank="+rank, r.URL, r.Rank)
+	case verb == 'v', verb == 's', verb == 'e', verb == 'E':
+		fmt.Fprintf(f, "%s ("+rank+")", r.URL, r.Rank)
+	case verb == 'q':
+		fmt.Fprintf(f, "%q "+rank, r.URL, r.Rank)
+	default:
+		fmt.Fprintf(f, "%%!%c(pagerank.Result=%s (%g))", verb, r.URL, r.Rank)
+	}
+}
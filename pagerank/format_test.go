@@ -0,0 +1,75 @@
+package pagerank
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCalculatorString(t *testing.T) {
+	calc := New()
+	got := fmt.Sprintf("%v", calc)
+	if got != "Calculator{damping: 0.85, iterations: 50}" {
+		t.Errorf("unexpected String output %q", got)
+	}
+
+	var damping float64
+	var iterations int
+	calc.SetDamping(0.9).SetIterations(20).SetMaxRankCap(0.3).
+		SetRestartProbabilities(map[string]float64{"a": 0.5, "b": 0.2, "c": 0.1})
+	got = calc.String()
+	if _, err := fmt.Sscanf(got, "Calculator{damping: %g, iterations: %d,", &damping, &iterations); err != nil {
+		t.Fatalf("String output %q not parseable: %v", got, err)
+	}
+	if damping != 0.9 || iterations != 20 {
+		t.Errorf("parsed damping=%v iterations=%d from %q", damping, iterations, got)
+	}
+	for _, want := range []string{"maxRankCap: 0.3", "restart: 3 seeds"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String output %q missing %q", got, want)
+		}
+	}
+}
+
+func TestCalculatorGoString(t *testing.T) {
+	calc := New().SetRestartProbabilities(map[string]float64{"b": 0.2, "a": 0.5})
+	got := fmt.Sprintf("%#v", calc)
+	if !strings.HasPrefix(got, "&pagerank.Calculator{") || !strings.HasSuffix(got, "}") {
+		t.Fatalf("unexpected GoString output %q", got)
+	}
+	for _, want := range []string{"damping: 0.85", "iterations: 50", `restart: {"a": 0.5, "b": 0.2}`, "rng: nil"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GoString output %q missing %q", got, want)
+		}
+	}
+}
+
+func TestResultFormat(t *testing.T) {
+	r := Result{URL: "https://example.com/a", Rank: 0.125}
+	cases := []struct {
+		format string
+		want   string
+	}{
+		{"%v", "https://example.com/a (0.125)"},
+		{"%s", "https://example.com/a (0.125)"},
+		{"%+v", "URL=https://example.com/a Rank=0.125"},
+		{"%#v", `pagerank.Result{URL:"https://example.com/a", Rank:0.125}`},
+		{"%q", `"https://example.com/a" 0.125`},
+		{"%e", "https://example.com/a (1.250000e-01)"},
+		{"%.2e", "https://example.com/a (1.25e-01)"},
+		{"%.1v", "https://example.com/a (0.1)"},
+		{"%d", "%!d(pagerank.Result=https://example.com/a (0.125))"},
+	}
+	for _, tc := range cases {
+		if got := fmt.Sprintf(tc.format, r); got != tc.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tc.format, got, tc.want)
+		}
+	}
+
+	if got := fmt.Sprintln(r, Result{URL: "b", Rank: 1}); got != "https://example.com/a (0.125) b (1)\n" {
+		t.Errorf("Sprintln = %q", got)
+	}
+	if got := fmt.Sprint([]Result{r}); got != "[https://example.com/a (0.125)]" {
+		t.Errorf("Sprint of a slice = %q", got)
+	}
+}
@@ -0,0 +1,61 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+)
+
+// CalculateFractional estimates the ranks after a fractional number of
+// iterations, such as 7.5, by interpolating linearly between the rank
+// vectors after floor(exactIterations) and ceil(exactIterations)
+// iterations. Both come from a single run. A whole number gives exactly
+// what Calculate gives with that many iterations, and values below 1
+// interpolate from the uniform starting vector. Non-positive or NaN
+// values return an empty result.
+func (c *Calculator) CalculateFractional(backlinks map[string][]string, outlinksCount map[string]int, exactIterations float64) []Result {
+	if !(exactIterations > 0) {
+		return []Result{}
+	}
+	g, err := c.index(backlinks, outlinksCount)
+	if err != nil || len(g.urls) == 0 {
+		return []Result{}
+	}
+	lo, hi := int(math.Floor(exactIterations)), int(math.Ceil(exactIterations))
+
+	var floor []float64
+	if lo == 0 {
+		floor = make([]float64, len(g.urls))
+		for i := range floor {
+			floor[i] = 1.0 / float64(len(g.urls))
+		}
+	}
+	// The clone runs on c's behalf, so it logs where c does.
+	calc := c.Clone()
+	calc.rankLog, calc.diagnostic = c.rankLog, c.diagnostic
+	calc.iterations = hi
+	calc.observe = func(iteration int, urls []string, rank []float64) {
+		if c.observe != nil {
+			c.observe(iteration, urls, rank)
+		}
+		if iteration == lo {
+			floor = append([]float64(nil), rank...)
+		}
+	}
+	rank, err := calc.run(context.Background(), g, 1)
+	c.history = calc.history
+	if err != nil {
+		return []Result{}
+	}
+	if lo != hi {
+		// With a tolerance set the run may stop before lo; the last
+		// vector then stands for both ends.
+		if floor == nil {
+			floor = rank
+		}
+		frac := exactIterations - float64(lo)
+		for i := range rank {
+			rank[i] = floor[i] + frac*(rank[i]-floor[i])
+		}
+	}
+	return resultsOf(g, rank, c.focus)
+}
@@ -0,0 +1,41 @@
+package pagerank
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCalculateFractionalWholeMatchesCalculate(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(200, 3, 3)
+	got := New().CalculateFractional(backlinks, outlinks, 50)
+	want := New().SetIterations(50).Calculate(backlinks, outlinks)
+	if !reflect.DeepEqual(got, want) {
+		t.Error("50.0 iterations differ from Calculate with 50")
+	}
+}
+
+func TestCalculateFractionalBetweenNeighbours(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	floor := ScoreMap(New().SetIterations(2).Calculate(backlinks, outlinks))
+	ceil := ScoreMap(New().SetIterations(3).Calculate(backlinks, outlinks))
+	for _, r := range New().CalculateFractional(backlinks, outlinks, 2.25) {
+		lo, hi := math.Min(floor[r.URL], ceil[r.URL]), math.Max(floor[r.URL], ceil[r.URL])
+		if r.Rank < lo-1e-15 || r.Rank > hi+1e-15 {
+			t.Errorf("%s: %v outside [%v, %v]", r.URL, r.Rank, lo, hi)
+		}
+		if want := floor[r.URL] + 0.25*(ceil[r.URL]-floor[r.URL]); math.Abs(r.Rank-want) > 1e-15 {
+			t.Errorf("%s: %v, want %v", r.URL, r.Rank, want)
+		}
+	}
+
+	half := ScoreMap(New().CalculateFractional(backlinks, outlinks, 0.5))
+	for url, r := range ScoreMap(New().SetIterations(1).Calculate(backlinks, outlinks)) {
+		if want := (0.25 + r) / 2; math.Abs(half[url]-want) > 1e-15 {
+			t.Errorf("0.5 iterations: %s = %v, want %v", url, half[url], want)
+		}
+	}
+	if got := New().CalculateFractional(backlinks, outlinks, 0); len(got) != 0 {
+		t.Errorf("zero iterations gave %v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package pagerank
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// GeneratePowerLawGraph builds a synthetic link graph by preferential
+// attachment: each new page links to up to degree earlier pages, choosing
+// targets in proportion to the inlinks they already have. The resulting
+// in-degree distribution follows a power law, like real web graphs.
+func GeneratePowerLawGraph(nodes, degree int, seed int64) (map[string][]string, map[string]int) {
+	rng := rand.New(rand.NewSource(seed))
+	backlinks := make(map[string][]string, nodes)
+	outlinks := make(map[string]int, nodes)
+	if nodes <= 0 {
+		return backlinks, outlinks
+	}
+
+	names := make([]string, nodes)
+	for i := range names {
+		names[i] = "page-" + strconv.Itoa(i)
+	}
+	// endpoints holds one entry per received link plus one per page, so a
+	// uniform draw from it is a draw weighted by in-degree + 1.
+	endpoints := make([]int32, 0, nodes*(degree+1))
+	endpoints = append(endpoints, 0)
+
+	for i := 1; i < nodes; i++ {
+		k := degree
+		if k > i {
+			k = i
+		}
+		for j := 0; j < k; j++ {
+			dst := int(endpoints[rng.Intn(len(endpoints))])
+			backlinks[names[dst]] = append(backlinks[names[dst]], names[i])
+			outlinks[names[i]]++
+			endpoints = append(endpoints, int32(dst))
+		}
+		endpoints = append(endpoints, int32(i))
+	}
+	return backlinks, outlinks
+}
+
+// EdgeCount returns the number of links in a backlinks map.
+func EdgeCount(backlinks map[string][]string) int {
+	n := 0
+	for _, sources := range backlinks {
+		n += len(sources)
+	}
+	return n
+}
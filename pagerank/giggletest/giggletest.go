@@ -0,0 +1,68 @@
+// Package giggletest provides assertions for tests of code that uses
+// package pagerank, comparing ranks within a tolerance instead of exactly.
+package giggletest
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/Harsh-Pratap-Singh/Search_Engine/pagerank"
+)
+
+// AssertResultsEqual reports an error on t for every URL in only one of
+// expected and actual, and for every shared URL whose ranks differ by more
+// than rankTolerance. With checkOrder, actual must also list the URLs in
+// expected's order.
+func AssertResultsEqual(t testing.TB, expected, actual []pagerank.Result, rankTolerance float64, checkOrder bool) {
+	t.Helper()
+	want := pagerank.ScoreMap(expected)
+	got := pagerank.ScoreMap(actual)
+	for _, url := range sortedKeys(want) {
+		g, ok := got[url]
+		switch {
+		case !ok:
+			t.Errorf("missing result for %s (want rank %g)", url, want[url])
+		case math.Abs(g-want[url]) > rankTolerance || math.IsNaN(g):
+			t.Errorf("%s: rank %g, want %g within %g", url, g, want[url], rankTolerance)
+		}
+	}
+	for _, url := range sortedKeys(got) {
+		if _, ok := want[url]; !ok {
+			t.Errorf("unexpected result for %s (rank %g)", url, got[url])
+		}
+	}
+	if !checkOrder {
+		return
+	}
+	for i := 0; i < len(expected) && i < len(actual); i++ {
+		if expected[i].URL != actual[i].URL {
+			t.Errorf("position %d: got %s, want %s", i+1, actual[i].URL, expected[i].URL)
+			return
+		}
+	}
+}
+
+// AssertTopK reports an error on t unless the first len(expectedTop)
+// results are expectedTop, in that order.
+func AssertTopK(t testing.TB, results []pagerank.Result, expectedTop []string) {
+	t.Helper()
+	if len(results) < len(expectedTop) {
+		t.Errorf("got %d results, want at least %d", len(results), len(expectedTop))
+		return
+	}
+	for i, url := range expectedTop {
+		if results[i].URL != url {
+			t.Errorf("position %d: got %s, want %s", i+1, results[i].URL, url)
+		}
+	}
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,75 @@
+package giggletest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Harsh-Pratap-Singh/Search_Engine/pagerank"
+)
+
+// recorder collects the errors an assertion reports instead of failing
+// the real test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func sampleResults() []pagerank.Result {
+	return []pagerank.Result{{URL: "a", Rank: 0.5}, {URL: "b", Rank: 0.3}, {URL: "c", Rank: 0.2}}
+}
+
+func TestAssertResultsEqual(t *testing.T) {
+	want := sampleResults()
+	near := []pagerank.Result{{URL: "a", Rank: 0.5001}, {URL: "c", Rank: 0.2}, {URL: "b", Rank: 0.3}}
+	cases := []struct {
+		name       string
+		actual     []pagerank.Result
+		tolerance  float64
+		checkOrder bool
+		errors     int
+	}{
+		{"within tolerance", near, 1e-3, false, 0},
+		{"outside tolerance", near, 1e-6, false, 1},
+		{"order checked", near, 1e-3, true, 1},
+		{"missing and extra", []pagerank.Result{{URL: "a", Rank: 0.5}, {URL: "b", Rank: 0.3}, {URL: "d", Rank: 0.2}}, 0, false, 2},
+	}
+	for _, tc := range cases {
+		r := &recorder{TB: t}
+		AssertResultsEqual(r, want, tc.actual, tc.tolerance, tc.checkOrder)
+		if len(r.errors) != tc.errors {
+			t.Errorf("%s: got %d errors %q, want %d", tc.name, len(r.errors), r.errors, tc.errors)
+		}
+	}
+}
+
+func TestAssertTopK(t *testing.T) {
+	cases := []struct {
+		top    []string
+		errors int
+	}{
+		{[]string{"a", "b"}, 0},
+		{nil, 0},
+		{[]string{"b", "a"}, 2},
+		{[]string{"a", "b", "c", "d"}, 1},
+	}
+	for _, tc := range cases {
+		r := &recorder{TB: t}
+		AssertTopK(r, sampleResults(), tc.top)
+		if len(r.errors) != tc.errors {
+			t.Errorf("top %v: got %d errors %q, want %d", tc.top, len(r.errors), r.errors, tc.errors)
+		}
+	}
+
+	backlinks := map[string][]string{"a": {"b", "c"}, "b": {"c"}, "c": {"a"}}
+	outlinks := map[string]int{"a": 1, "b": 1, "c": 2}
+	calc := pagerank.New()
+	results := calc.Calculate(backlinks, outlinks)
+	AssertResultsEqual(t, results, calc.CalculateParallel(backlinks, outlinks, 2), 1e-12, true)
+	AssertTopK(t, results, []string{"a", "c"})
+}
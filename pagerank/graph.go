@@ -0,0 +1,706 @@
+package pagerank
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Weights for GraphHealthScore. They sum to 1 so the score is a weighted
+// geometric mean: any metric at zero drives the whole score to zero.
+const (
+	healthInlinkWeight   = 0.3
+	healthOutlinkWeight  = 0.3
+	healthSelfLoopWeight = 0.2
+	healthDiameterWeight = 0.2
+
+	diameterProbes = 8
+)
+
+// GraphHealthScore summarizes the link graph as a single value in [0, 1]
+// built from the fraction of nodes with an inlink, the fraction with an
+// outlink, one minus the fraction of edges that are self-loops, and the
+// reciprocal of the estimated diameter.
+func GraphHealthScore(backlinks map[string][]string, outlinksCount map[string]int) float64 {
+	nodes := collectURLs(backlinks, outlinksCount)
+	if len(nodes) == 0 {
+		return 0
+	}
+	forward := forwardLinks(backlinks)
+
+	var withIn, withOut, edges, selfLoops int
+	for _, url := range nodes {
+		if len(backlinks[url]) > 0 {
+			withIn++
+		}
+		if outlinksCount[url] > 0 || len(forward[url]) > 0 {
+			withOut++
+		}
+		for _, src := range backlinks[url] {
+			edges++
+			if src == url {
+				selfLoops++
+			}
+		}
+	}
+
+	n := float64(len(nodes))
+	loopFree := 1.0
+	if edges > 0 {
+		loopFree = 1 - float64(selfLoops)/float64(edges)
+	}
+	diameter := estimateDiameter(nodes, forward)
+	if diameter < 1 {
+		diameter = 1
+	}
+
+	metrics := []struct{ value, weight float64 }{
+		{float64(withIn) / n, healthInlinkWeight},
+		{float64(withOut) / n, healthOutlinkWeight},
+		{loopFree, healthSelfLoopWeight},
+		{1 / float64(diameter), healthDiameterWeight},
+	}
+	var logSum float64
+	for _, m := range metrics {
+		if m.value <= 0 {
+			return 0
+		}
+		logSum += m.weight * math.Log(m.value)
+	}
+	return math.Exp(logSum)
+}
+
+// estimateDiameter runs a BFS from a handful of evenly spaced nodes and
+// returns the longest shortest path seen.
+func estimateDiameter(nodes []string, forward map[string][]string) int {
+	step := 1
+	if len(nodes) > diameterProbes {
+		step = len(nodes) / diameterProbes
+	}
+	longest := 0
+	for i := 0; i < len(nodes); i += step {
+		dist := map[string]int{nodes[i]: 0}
+		queue := []string{nodes[i]}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			for _, next := range forward[cur] {
+				if _, seen := dist[next]; seen {
+					continue
+				}
+				dist[next] = dist[cur] + 1
+				if dist[next] > longest {
+					longest = dist[next]
+				}
+				queue = append(queue, next)
+			}
+		}
+	}
+	return longest
+}
+
+// probeIterations is how many iterations EstimateIterationsNeeded and
+// EstimateMixingTime run.
+const probeIterations = 10
+
+// EstimateIterationsNeeded predicts the iteration count at which
+// SetTolerance(tolerance) would stop. It runs probeIterations iterations,
+// takes the geometric mean of the ratio between successive L1 deltas over
+// the second half of the probe as the convergence rate, and extrapolates
+// from the last delta. It is an error if the deltas are not shrinking.
+func EstimateIterationsNeeded(backlinks map[string][]string, outlinksCount map[string]int, damping, tolerance float64) (int, error) {
+	if damping <= 0 || damping >= 1 {
+		return 0, fmt.Errorf("pagerank: damping %g outside (0, 1)", damping)
+	}
+	if tolerance <= 0 {
+		return 0, fmt.Errorf("pagerank: tolerance %g must be positive", tolerance)
+	}
+	deltas, err := probeDeltas(New().SetDamping(damping), backlinks, outlinksCount)
+	if err != nil {
+		return 0, err
+	}
+	if len(deltas) == 0 {
+		return 0, fmt.Errorf("pagerank: cannot estimate convergence of an empty graph")
+	}
+	for k, d := range deltas {
+		if d < tolerance {
+			return k + 1, nil
+		}
+	}
+	rate := deltaRate(deltas)
+	if !(rate < 1) {
+		return 0, fmt.Errorf("pagerank: cannot estimate convergence, L1 delta went from %g to %g", deltas[len(deltas)/2-1], deltas[len(deltas)-1])
+	}
+	return len(deltas) + int(math.Ceil(math.Log(tolerance/deltas[len(deltas)-1])/math.Log(rate))), nil
+}
+
+// EstimateMixingTime estimates how many steps bring any starting
+// distribution within epsilon of the stationary one, as
+// log(1/epsilon) / (1 - |λ2|). The second eigenvalue |λ2| is taken to be
+// the L1 delta's rate of decay over a probe as in EstimateIterationsNeeded,
+// capped at the damping factor, which bounds it; the cap is also used when
+// the probe cannot measure a rate. The estimate is at least 1, or 0 for an
+// empty or rejected graph or an epsilon outside (0, 1).
+func (c *Calculator) EstimateMixingTime(backlinks map[string][]string, outlinksCount map[string]int, epsilon float64) int {
+	if !(epsilon > 0 && epsilon < 1) {
+		return 0
+	}
+	deltas, err := probeDeltas(c, backlinks, outlinksCount)
+	if err != nil || len(deltas) == 0 {
+		return 0
+	}
+	lambda := c.damping
+	if rate := deltaRate(deltas); rate >= 0 && rate < lambda {
+		lambda = rate
+	}
+	return max(1, int(math.Ceil(math.Log(1/epsilon)/(1-lambda))))
+}
+
+// probeDeltas runs probeIterations iterations of c from the uniform vector
+// and returns the L1 distance each one moved the ranks.
+func probeDeltas(c *Calculator, backlinks map[string][]string, outlinksCount map[string]int) ([]float64, error) {
+	calc := c.Clone().SetIterations(probeIterations)
+	calc.tolerance = 0
+	var deltas, prev []float64
+	calc.observe = func(_ int, _ []string, rank []float64) {
+		if prev == nil {
+			prev = make([]float64, len(rank))
+			for i := range prev {
+				prev[i] = 1 / float64(len(rank))
+			}
+		}
+		deltas = append(deltas, l1Distance(prev, rank))
+		prev = append(prev[:0], rank...)
+	}
+	if _, err := calc.CalculateContext(context.Background(), backlinks, outlinksCount); err != nil {
+		return nil, err
+	}
+	return deltas, nil
+}
+
+// deltaRate is the geometric mean ratio between successive deltas over the
+// second half of a probe. It is NaN if they had already reached zero by
+// the midpoint.
+func deltaRate(deltas []float64) float64 {
+	mid, last := deltas[len(deltas)/2-1], deltas[len(deltas)-1]
+	return math.Pow(last/mid, 1/float64(len(deltas)-len(deltas)/2))
+}
+
+// Walk visits pages breadth-first from startURL along outlinks, calling fn
+// with each page's distance from startURL and its rank in ranks (0 if
+// absent). Each page is visited once, and pages at the same depth are
+// visited in the order their links were found. The walk stops as soon as
+// fn returns false.
+func Walk(startURL string, backlinks map[string][]string, ranks map[string]float64, fn func(url string, depth int, rank float64) bool) {
+	forward := forwardLinks(backlinks)
+	depth := map[string]int{startURL: 0}
+	for queue := []string{startURL}; len(queue) > 0; queue = queue[1:] {
+		url := queue[0]
+		if !fn(url, depth[url], ranks[url]) {
+			return
+		}
+		for _, next := range forward[url] {
+			if _, seen := depth[next]; !seen {
+				depth[next] = depth[url] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+}
+
+// collectURLs returns every URL mentioned in either map, sorted.
+func collectURLs(backlinks map[string][]string, outlinksCount map[string]int) []string {
+	seen := make(map[string]bool)
+	for url, sources := range backlinks {
+		seen[url] = true
+		for _, src := range sources {
+			seen[src] = true
+		}
+	}
+	for url := range outlinksCount {
+		seen[url] = true
+	}
+	out := make([]string, 0, len(seen))
+	for url := range seen {
+		out = append(out, url)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// urlCountExceeds reports whether backlinks and outlinksCount name more
+// than limit distinct pages, stopping as soon as they do.
+func urlCountExceeds(backlinks map[string][]string, outlinksCount map[string]int, limit int) bool {
+	seen := make(map[string]bool)
+	add := func(url string) bool {
+		seen[url] = true
+		return len(seen) > limit
+	}
+	for url, sources := range backlinks {
+		if add(url) {
+			return true
+		}
+		for _, src := range sources {
+			if add(src) {
+				return true
+			}
+		}
+	}
+	for url := range outlinksCount {
+		if add(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardLinks transposes backlinks into source -> targets adjacency.
+func forwardLinks(backlinks map[string][]string) map[string][]string {
+	forward := make(map[string][]string)
+	targets := make([]string, 0, len(backlinks))
+	for url := range backlinks {
+		targets = append(targets, url)
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		for _, src := range backlinks[target] {
+			forward[src] = append(forward[src], target)
+		}
+	}
+	return forward
+}
+
+// FindConnectedComponents groups pages that are connected when link
+// direction is ignored, using union-find. Each component is sorted, and
+// components are ordered largest first.
+func FindConnectedComponents(backlinks map[string][]string) [][]string {
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(x string) string {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	union := func(a, b string) {
+		ra, rb := find(a), find(b)
+		if ra != rb {
+			if ra < rb {
+				parent[rb] = ra
+			} else {
+				parent[ra] = rb
+			}
+		}
+	}
+
+	nodes := collectURLs(backlinks, nil)
+	for _, url := range nodes {
+		parent[url] = url
+	}
+	for target, sources := range backlinks {
+		for _, src := range sources {
+			union(src, target)
+		}
+	}
+
+	groups := make(map[string][]string)
+	for _, url := range nodes {
+		root := find(url)
+		groups[root] = append(groups[root], url)
+	}
+	components := make([][]string, 0, len(groups))
+	for _, members := range groups {
+		components = append(components, members)
+	}
+	sortComponents(components)
+	return components
+}
+
+// FindWeaklyConnectedComponents finds the same components as
+// FindConnectedComponents by breadth-first search over the undirected
+// graph.
+func FindWeaklyConnectedComponents(backlinks map[string][]string) [][]string {
+	undirected := undirectedLinks(backlinks)
+	visited := make(map[string]bool)
+	var components [][]string
+	for _, start := range collectURLs(backlinks, nil) {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		component := []string{start}
+		for queue := []string{start}; len(queue) > 0; queue = queue[1:] {
+			for _, next := range undirected[queue[0]] {
+				if !visited[next] {
+					visited[next] = true
+					component = append(component, next)
+					queue = append(queue, next)
+				}
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+	sortComponents(components)
+	return components
+}
+
+// undirectedLinks lists every page's neighbours, ignoring link direction.
+func undirectedLinks(backlinks map[string][]string) map[string][]string {
+	undirected := make(map[string][]string)
+	for target, sources := range backlinks {
+		for _, src := range sources {
+			undirected[target] = append(undirected[target], src)
+			undirected[src] = append(undirected[src], target)
+		}
+	}
+	return undirected
+}
+
+// symmetrize returns copies of the maps with the reverse of every link
+// added where it is missing, each added link counted as an outlink of its
+// new source. Self-links are their own reverse.
+func symmetrize(backlinks map[string][]string, outlinksCount map[string]int) (map[string][]string, map[string]int) {
+	sym := make(map[string][]string, len(backlinks))
+	has := make(map[edge]bool)
+	for target, sources := range backlinks {
+		sym[target] = append([]string(nil), sources...)
+		for _, src := range sources {
+			has[edge{src, target}] = true
+		}
+	}
+	outlinks := make(map[string]int, len(outlinksCount))
+	for url, n := range outlinksCount {
+		outlinks[url] = n
+	}
+	for _, target := range collectURLs(backlinks, nil) {
+		for _, src := range backlinks[target] {
+			reverse := edge{target, src}
+			if has[reverse] {
+				continue
+			}
+			has[reverse] = true
+			sym[src] = append(sym[src], target)
+			outlinks[target]++
+		}
+	}
+	return sym, outlinks
+}
+
+// IsBipartite 2-colours the graph, ignoring link direction, and reports
+// whether every link joins pages of different colours. On success each
+// page is mapped to partition 0 or 1, with the alphabetically first page
+// of every component in partition 0; otherwise the map is nil.
+func IsBipartite(backlinks map[string][]string) (bool, map[string]int) {
+	undirected := undirectedLinks(backlinks)
+	partition := make(map[string]int)
+	for _, start := range collectURLs(backlinks, nil) {
+		if _, ok := partition[start]; ok {
+			continue
+		}
+		partition[start] = 0
+		for queue := []string{start}; len(queue) > 0; queue = queue[1:] {
+			url := queue[0]
+			for _, next := range undirected[url] {
+				p, ok := partition[next]
+				if !ok {
+					partition[next] = 1 - partition[url]
+					queue = append(queue, next)
+				} else if p == partition[url] {
+					return false, nil
+				}
+			}
+		}
+	}
+	return true, partition
+}
+
+// BipartitePageRank ranks the graph as usual and splits the results by
+// IsBipartite's partitions, each still sorted by rank. Pages with no links
+// fall in partition 0. Both slices are nil if the graph is not bipartite.
+func BipartitePageRank(backlinks map[string][]string, outlinksCount map[string]int, calc *Calculator) (partition0, partition1 []Result) {
+	ok, partition := IsBipartite(backlinks)
+	if !ok {
+		return nil, nil
+	}
+	for _, r := range calc.Calculate(backlinks, outlinksCount) {
+		if partition[r.URL] == 1 {
+			partition1 = append(partition1, r)
+		} else {
+			partition0 = append(partition0, r)
+		}
+	}
+	return partition0, partition1
+}
+
+// FindSCCs returns the strongly connected components of the link graph,
+// each sorted, largest first. Every page is in exactly one component; a
+// page on no cycle forms a component by itself.
+func FindSCCs(backlinks map[string][]string) [][]string {
+	urls := collectURLs(backlinks, nil)
+	index := make(map[string]int, len(urls))
+	for i, url := range urls {
+		index[url] = i
+	}
+	forward := make([][]int, len(urls))
+	for target, sources := range backlinks {
+		for _, src := range sources {
+			forward[index[src]] = append(forward[index[src]], index[target])
+		}
+	}
+
+	// Iterative Tarjan, so deep chains cannot overflow the stack.
+	const unvisited = -1
+	order := make([]int, len(urls))
+	low := make([]int, len(urls))
+	for i := range order {
+		order[i] = unvisited
+	}
+	onStack := make([]bool, len(urls))
+	var stack []int
+	var components [][]string
+	type frame struct{ node, next int }
+	counter := 0
+	for root := range urls {
+		if order[root] != unvisited {
+			continue
+		}
+		calls := []frame{{root, 0}}
+		order[root], low[root] = counter, counter
+		counter++
+		stack = append(stack, root)
+		onStack[root] = true
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			v := top.node
+			if top.next < len(forward[v]) {
+				w := forward[v][top.next]
+				top.next++
+				if order[w] == unvisited {
+					order[w], low[w] = counter, counter
+					counter++
+					stack = append(stack, w)
+					onStack[w] = true
+					calls = append(calls, frame{w, 0})
+				} else if onStack[w] && order[w] < low[v] {
+					low[v] = order[w]
+				}
+				continue
+			}
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				if parent := calls[len(calls)-1].node; low[v] < low[parent] {
+					low[parent] = low[v]
+				}
+			}
+			if low[v] == order[v] {
+				var component []string
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					component = append(component, urls[w])
+					if w == v {
+						break
+					}
+				}
+				components = append(components, component)
+			}
+		}
+	}
+	sortComponents(components)
+	return components
+}
+
+// FindArticulationPoints returns, sorted, the pages whose removal would
+// split their connected component, ignoring link direction.
+func FindArticulationPoints(backlinks map[string][]string) []string {
+	points, _ := biconnected(backlinks)
+	return points
+}
+
+// FindBiconnectedComponents partitions the links, ignoring direction, into
+// biconnected components: maximal groups with no articulation point
+// inside. Each link appears once, as a pair in sorted order; links within
+// a component are sorted, and components are ordered largest first.
+// Self-links are skipped.
+func FindBiconnectedComponents(backlinks map[string][]string) [][][2]string {
+	_, components := biconnected(backlinks)
+	return components
+}
+
+// biconnected runs Hopcroft-Tarjan over the undirected graph.
+func biconnected(backlinks map[string][]string) ([]string, [][][2]string) {
+	urls := collectURLs(backlinks, nil)
+	index := make(map[string]int, len(urls))
+	for i, url := range urls {
+		index[url] = i
+	}
+	adj := make([][]int, len(urls))
+	for url, neighbours := range undirectedLinks(backlinks) {
+		v := index[url]
+		for _, n := range dedupeSorted(neighbours) {
+			if w := index[n]; w != v {
+				adj[v] = append(adj[v], w)
+			}
+		}
+	}
+
+	const unvisited = -1
+	disc := make([]int, len(urls))
+	low := make([]int, len(urls))
+	for i := range disc {
+		disc[i] = unvisited
+	}
+	isPoint := make([]bool, len(urls))
+	var edges [][2]int
+	var components [][][2]string
+	type frame struct{ node, parent, next, children int }
+	counter := 0
+	for root := range urls {
+		if disc[root] != unvisited {
+			continue
+		}
+		disc[root], low[root] = counter, counter
+		counter++
+		calls := []frame{{root, unvisited, 0, 0}}
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			v := top.node
+			if top.next < len(adj[v]) {
+				w := adj[v][top.next]
+				top.next++
+				if disc[w] == unvisited {
+					top.children++
+					edges = append(edges, [2]int{v, w})
+					disc[w], low[w] = counter, counter
+					counter++
+					calls = append(calls, frame{w, v, 0, 0})
+				} else if w != top.parent && disc[w] < disc[v] {
+					edges = append(edges, [2]int{v, w})
+					if disc[w] < low[v] {
+						low[v] = disc[w]
+					}
+				}
+				continue
+			}
+			calls = calls[:len(calls)-1]
+			if len(calls) == 0 {
+				if top.children > 1 {
+					isPoint[v] = true
+				}
+				break
+			}
+			parent := &calls[len(calls)-1]
+			p := parent.node
+			if low[v] < low[p] {
+				low[p] = low[v]
+			}
+			if low[v] < disc[p] {
+				continue
+			}
+			if parent.parent != unvisited {
+				isPoint[p] = true
+			}
+			var component [][2]string
+			for {
+				e := edges[len(edges)-1]
+				edges = edges[:len(edges)-1]
+				a, b := urls[e[0]], urls[e[1]]
+				if b < a {
+					a, b = b, a
+				}
+				component = append(component, [2]string{a, b})
+				if e == [2]int{p, v} {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	var points []string
+	for i, url := range urls {
+		if isPoint[i] {
+			points = append(points, url)
+		}
+	}
+	for _, c := range components {
+		sort.Slice(c, func(i, j int) bool {
+			if c[i][0] != c[j][0] {
+				return c[i][0] < c[j][0]
+			}
+			return c[i][1] < c[j][1]
+		})
+	}
+	sort.Slice(components, func(i, j int) bool {
+		if len(components[i]) != len(components[j]) {
+			return len(components[i]) > len(components[j])
+		}
+		a, b := components[i][0], components[j][0]
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		return a[1] < b[1]
+	})
+	return points, components
+}
+
+// LargestComponent returns the subgraph induced by the largest weakly
+// connected component, with outlink counts derived from its edges.
+func LargestComponent(backlinks map[string][]string) (map[string][]string, map[string]int) {
+	components := FindWeaklyConnectedComponents(backlinks)
+	if len(components) == 0 {
+		return map[string][]string{}, map[string]int{}
+	}
+	return inducedSubgraph(backlinks, components[0])
+}
+
+// LargestSCC returns the subgraph induced by the largest strongly
+// connected component, with outlink counts derived from its edges, and the
+// fraction of all pages that component holds.
+func LargestSCC(backlinks map[string][]string) (sccBacklinks map[string][]string, sccOutlinks map[string]int, fraction float64) {
+	components := FindSCCs(backlinks)
+	if len(components) == 0 {
+		return map[string][]string{}, map[string]int{}, 0
+	}
+	total := 0
+	for _, c := range components {
+		total += len(c)
+	}
+	sccBacklinks, sccOutlinks = inducedSubgraph(backlinks, components[0])
+	return sccBacklinks, sccOutlinks, float64(len(components[0])) / float64(total)
+}
+
+// inducedSubgraph keeps only the links whose endpoints are both in nodes.
+func inducedSubgraph(backlinks map[string][]string, nodes []string) (map[string][]string, map[string]int) {
+	keep := make(map[string]bool, len(nodes))
+	outlinks := make(map[string]int, len(nodes))
+	for _, url := range nodes {
+		keep[url] = true
+		outlinks[url] = 0
+	}
+	sub := make(map[string][]string)
+	for _, target := range nodes {
+		for _, src := range backlinks[target] {
+			if keep[src] {
+				sub[target] = append(sub[target], src)
+				outlinks[src]++
+			}
+		}
+	}
+	return sub, outlinks
+}
+
+func sortComponents(components [][]string) {
+	for _, c := range components {
+		sort.Strings(c)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		if len(components[i]) != len(components[j]) {
+			return len(components[i]) > len(components[j])
+		}
+		return components[i][0] < components[j][0]
+	})
+}
@@ -0,0 +1,333 @@
+package pagerank
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGraphHealthScoreSampleGraph(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	score := GraphHealthScore(backlinks, outlinks)
+	if score <= 0.5 || score > 1 {
+		t.Errorf("expected sample graph health in (0.5, 1], got %.4f", score)
+	}
+}
+
+func TestGraphHealthScoreSelfLoop(t *testing.T) {
+	backlinks := map[string][]string{"page-a": {"page-a"}}
+	outlinks := map[string]int{"page-a": 1}
+	if score := GraphHealthScore(backlinks, outlinks); score > 1e-9 {
+		t.Errorf("expected single self-loop graph to score ~0, got %.4f", score)
+	}
+}
+
+func TestGraphHealthScoreEmpty(t *testing.T) {
+	if score := GraphHealthScore(nil, nil); score != 0 {
+		t.Errorf("expected empty graph to score 0, got %.4f", score)
+	}
+}
+
+func TestGeneratePowerLawGraph(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(1000, 3, 1)
+
+	nodes := collectURLs(backlinks, outlinks)
+	if len(nodes) != 1000 {
+		t.Fatalf("expected 1000 nodes, got %d", len(nodes))
+	}
+	total := 0
+	for _, n := range outlinks {
+		total += n
+	}
+	if got := EdgeCount(backlinks); got != total {
+		t.Errorf("edge count %d does not match outlink total %d", got, total)
+	}
+
+	maxIn := 0
+	for _, sources := range backlinks {
+		if len(sources) > maxIn {
+			maxIn = len(sources)
+		}
+	}
+	if maxIn < 30 {
+		t.Errorf("expected a heavy-tailed in-degree, max in-degree was %d", maxIn)
+	}
+
+	again, _ := GeneratePowerLawGraph(1000, 3, 1)
+	if EdgeCount(again) != EdgeCount(backlinks) || len(again["page-0"]) != len(backlinks["page-0"]) {
+		t.Error("same seed should produce the same graph")
+	}
+}
+
+func twoClusterGraph() map[string][]string {
+	return map[string][]string{
+		"a1": {"a2", "a3"},
+		"a2": {"a1"},
+		"a4": {"a3"},
+		"b1": {"b2"},
+		"b2": {"b1"},
+	}
+}
+
+func TestFindConnectedComponents(t *testing.T) {
+	for name, find := range map[string]func(map[string][]string) [][]string{
+		"union-find": FindConnectedComponents,
+		"bfs":        FindWeaklyConnectedComponents,
+	} {
+		components := find(twoClusterGraph())
+		if len(components) != 2 {
+			t.Fatalf("%s: expected 2 components, got %v", name, components)
+		}
+		if len(components[0]) != 4 || len(components[1]) != 2 {
+			t.Errorf("%s: expected sizes 4 and 2, got %v", name, components)
+		}
+		if components[0][0] != "a1" || components[1][0] != "b1" {
+			t.Errorf("%s: components not sorted: %v", name, components)
+		}
+	}
+}
+
+func TestLargestComponent(t *testing.T) {
+	backlinks, outlinks := LargestComponent(twoClusterGraph())
+	if len(outlinks) != 4 {
+		t.Fatalf("expected 4 nodes in largest component, got %v", outlinks)
+	}
+	if _, ok := backlinks["b1"]; ok {
+		t.Error("second cluster should not be in the largest component")
+	}
+	if outlinks["a2"] != 1 || outlinks["a3"] != 2 || outlinks["a1"] != 1 {
+		t.Errorf("unexpected outlink counts %v", outlinks)
+	}
+}
+
+func TestIsBipartite(t *testing.T) {
+	// Reviews link to products, which never link back.
+	reviews := map[string][]string{
+		"product-1": {"review-a", "review-b"},
+		"product-2": {"review-b", "review-c"},
+	}
+	ok, partition := IsBipartite(reviews)
+	if !ok {
+		t.Fatal("review graph should be bipartite")
+	}
+	for _, url := range []string{"review-a", "review-b", "review-c"} {
+		if partition[url] == partition["product-1"] || partition[url] != partition["product-2"]^1 {
+			t.Errorf("%s in the same partition as the products: %v", url, partition)
+		}
+	}
+
+	triangle := map[string][]string{"b": {"a"}, "c": {"b"}, "a": {"c"}}
+	if ok, partition := IsBipartite(triangle); ok || partition != nil {
+		t.Errorf("triangle reported bipartite: %v", partition)
+	}
+}
+
+func TestBipartitePageRank(t *testing.T) {
+	reviews := map[string][]string{
+		"product-1": {"review-a", "review-b"},
+		"product-2": {"review-b", "review-c"},
+	}
+	outlinks := map[string]int{"review-a": 1, "review-b": 2, "review-c": 1, "product-1": 0, "product-2": 0}
+	part0, part1 := BipartitePageRank(reviews, outlinks, New())
+	if len(part0)+len(part1) != 5 {
+		t.Fatalf("expected 5 results across partitions, got %d and %d", len(part0), len(part1))
+	}
+	products, others := part0, part1
+	if len(part0) != 2 {
+		products, others = part1, part0
+	}
+	if len(products) != 2 || len(others) != 3 {
+		t.Fatalf("unexpected split %v / %v", part0, part1)
+	}
+	for _, r := range products {
+		if !strings.HasPrefix(r.URL, "product-") {
+			t.Errorf("%s grouped with the products", r.URL)
+		}
+	}
+
+	triangle := map[string][]string{"b": {"a"}, "c": {"b"}, "a": {"c"}}
+	if p0, p1 := BipartitePageRank(triangle, map[string]int{"a": 1, "b": 1, "c": 1}, New()); p0 != nil || p1 != nil {
+		t.Errorf("expected nil partitions for a triangle, got %v %v", p0, p1)
+	}
+}
+
+func TestFindSCCs(t *testing.T) {
+	// a <-> b, b -> c, c -> d -> e -> c, f alone.
+	backlinks := map[string][]string{
+		"a": {"b"},
+		"b": {"a"},
+		"c": {"b", "e"},
+		"d": {"c"},
+		"e": {"d"},
+		"f": nil,
+	}
+	got := fmt.Sprint(FindSCCs(backlinks))
+	if want := "[[c d e] [a b] [f]]"; got != want {
+		t.Errorf("FindSCCs = %s, want %s", got, want)
+	}
+
+	backlinks, _ = sampleGraph()
+	if sccs := FindSCCs(backlinks); len(sccs) != 1 || len(sccs[0]) != 4 {
+		t.Errorf("sample graph should be one SCC, got %v", sccs)
+	}
+}
+
+func TestLargestSCC(t *testing.T) {
+	backlinks, wantOut := sampleGraph()
+	sub, out, fraction := LargestSCC(backlinks)
+	if len(out) != 4 || fraction != 1 {
+		t.Fatalf("sample graph LSCC has %d pages (fraction %g), want all 4", len(out), fraction)
+	}
+	for url, n := range wantOut {
+		if out[url] != n || len(sub[url]) != len(backlinks[url]) {
+			t.Errorf("%s: %d outlinks and %d backlinks, want %d and %d", url, out[url], len(sub[url]), n, len(backlinks[url]))
+		}
+	}
+
+	// c -> d -> e -> c is reached from a <-> b but cannot reach back.
+	sub, out, fraction = LargestSCC(map[string][]string{
+		"a": {"b"},
+		"b": {"a"},
+		"c": {"b", "e"},
+		"d": {"c"},
+		"e": {"d"},
+		"f": nil,
+	})
+	if len(out) != 3 || fraction != 0.5 {
+		t.Fatalf("LSCC %v has fraction %g, want c, d, e and 0.5", out, fraction)
+	}
+	if got := fmt.Sprint(sub["c"]); got != "[e]" {
+		t.Errorf("c's backlinks in the LSCC = %s, want [e]", got)
+	}
+
+	if sub, out, fraction := LargestSCC(nil); len(sub) != 0 || len(out) != 0 || fraction != 0 {
+		t.Errorf("empty graph gave %v %v %g", sub, out, fraction)
+	}
+}
+
+func TestEstimateIterationsNeeded(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	for _, tol := range []float64{1e-6, 1e-9, 1e-12} {
+		got, err := EstimateIterationsNeeded(backlinks, outlinks, 0.85, tol)
+		if err != nil {
+			t.Fatalf("tolerance %g: %v", tol, err)
+		}
+		var ran int
+		calc := New().SetIterations(10000).SetTolerance(tol)
+		calc.observe = func(iteration int, _ []string, _ []float64) { ran = iteration }
+		calc.Calculate(backlinks, outlinks)
+		if math.Abs(float64(got-ran)) > 0.2*float64(ran) {
+			t.Errorf("tolerance %g: estimated %d iterations, needed %d", tol, got, ran)
+		}
+	}
+
+	if _, err := EstimateIterationsNeeded(nil, nil, 0.85, 1e-6); err == nil {
+		t.Error("expected an error for an empty graph")
+	}
+	if _, err := EstimateIterationsNeeded(backlinks, outlinks, 1, 1e-6); err == nil {
+		t.Error("expected an error for damping 1")
+	}
+}
+
+func TestEstimateMixingTime(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calc := New()
+	for _, eps := range []float64{1e-2, 1e-6} {
+		if got := calc.EstimateMixingTime(backlinks, outlinks, eps); got < 1 || got > 100 {
+			t.Errorf("epsilon %g: mixing time %d outside [1, 100]", eps, got)
+		}
+	}
+	loose := calc.EstimateMixingTime(backlinks, outlinks, 1e-2)
+	if tight := calc.EstimateMixingTime(backlinks, outlinks, 1e-6); tight <= loose {
+		t.Errorf("mixing time %d for 1e-6 should exceed %d for 1e-2", tight, loose)
+	}
+	if got := calc.EstimateMixingTime(backlinks, outlinks, 0); got != 0 {
+		t.Errorf("epsilon 0 gave %d", got)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	// home -> {about, blog}, blog -> {home, post}, post -> about
+	backlinks := map[string][]string{
+		"about": {"home", "post"},
+		"blog":  {"home"},
+		"home":  {"blog"},
+		"post":  {"blog"},
+	}
+	ranks := map[string]float64{"home": 0.4, "blog": 0.3}
+
+	var visited []string
+	depths := make(map[string]int)
+	Walk("home", backlinks, ranks, func(url string, depth int, rank float64) bool {
+		if rank != ranks[url] {
+			t.Errorf("%s visited with rank %v, want %v", url, rank, ranks[url])
+		}
+		visited = append(visited, url)
+		depths[url] = depth
+		return true
+	})
+	if got := strings.Join(visited, ","); got != "home,about,blog,post" {
+		t.Errorf("visit order %s", got)
+	}
+	if depths["post"] != 2 || depths["about"] != 1 {
+		t.Errorf("unexpected depths %v", depths)
+	}
+
+	var n int
+	Walk("home", backlinks, ranks, func(string, int, float64) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Errorf("walk continued after fn returned false: %d visits", n)
+	}
+
+	visited = nil
+	Walk("post", backlinks, nil, func(url string, _ int, _ float64) bool {
+		visited = append(visited, url)
+		return true
+	})
+	if got := strings.Join(visited, ","); got != "post,about" {
+		t.Errorf("walk from post visited %s", got)
+	}
+}
+
+func TestFindArticulationPoints(t *testing.T) {
+	// Two triangles joined through hub, plus a tail off c.
+	backlinks := map[string][]string{
+		"b":    {"a"},
+		"c":    {"b"},
+		"a":    {"c"},
+		"hub":  {"a"},
+		"x":    {"hub", "z"},
+		"y":    {"x"},
+		"z":    {"y", "z"},
+		"tail": {"c"},
+	}
+	got := FindArticulationPoints(backlinks)
+	if want := "a,c,hub,x"; strings.Join(got, ",") != want {
+		t.Errorf("articulation points %v, want %s", got, want)
+	}
+
+	components := FindBiconnectedComponents(backlinks)
+	want := []string{
+		"[[a b] [a c] [b c]]",
+		"[[x y] [x z] [y z]]",
+		"[[a hub]]",
+		"[[c tail]]",
+		"[[hub x]]",
+	}
+	if len(components) != len(want) {
+		t.Fatalf("got %d components, want %d: %v", len(components), len(want), components)
+	}
+	for i, c := range components {
+		if got := fmt.Sprint(c); got != want[i] {
+			t.Errorf("component %d = %s, want %s", i, got, want[i])
+		}
+	}
+
+	if got := FindArticulationPoints(map[string][]string{"b": {"a"}, "a": {"b"}}); len(got) != 0 {
+		t.Errorf("a single link has no articulation point, got %v", got)
+	}
+}
@@ -0,0 +1,64 @@
+package pagerank
+
+import "fmt"
+
+// GraphDB is a read-only source of link graph data, for graphs that live
+// outside the process.
+type GraphDB interface {
+	Nodes() ([]string, error)
+	Backlinks(url string) ([]string, error)
+}
+
+// BatchGraphDB is implemented by stores that can fetch many backlink sets
+// in one round trip. CalculateFromDB uses it when available.
+type BatchGraphDB interface {
+	GraphDB
+	BacklinksBatch(urls []string) (map[string][]string, error)
+}
+
+// LoadGraph reads the full graph from db. Outlink counts are derived from
+// the backlinks.
+func LoadGraph(db GraphDB) (map[string][]string, map[string]int, error) {
+	nodes, err := db.Nodes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("pagerank: list nodes: %w", err)
+	}
+
+	var backlinks map[string][]string
+	if batch, ok := db.(BatchGraphDB); ok {
+		backlinks, err = batch.BacklinksBatch(nodes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("pagerank: load backlinks: %w", err)
+		}
+	} else {
+		backlinks = make(map[string][]string, len(nodes))
+		for _, url := range nodes {
+			sources, err := db.Backlinks(url)
+			if err != nil {
+				return nil, nil, fmt.Errorf("pagerank: load backlinks for %s: %w", url, err)
+			}
+			if len(sources) > 0 {
+				backlinks[url] = sources
+			}
+		}
+	}
+
+	outlinksCount := make(map[string]int, len(nodes))
+	for _, url := range nodes {
+		outlinksCount[url] = 0
+	}
+	for _, sources := range backlinks {
+		for _, src := range sources {
+			outlinksCount[src]++
+		}
+	}
+	return backlinks, outlinksCount, nil
+}
+
+func (c *Calculator) CalculateFromDB(db GraphDB) ([]Result, error) {
+	backlinks, outlinksCount, err := LoadGraph(db)
+	if err != nil {
+		return nil, err
+	}
+	return c.Calculate(backlinks, outlinksCount), nil
+}
@@ -0,0 +1,41 @@
+package pagerank
+
+import (
+	"errors"
+	"testing"
+)
+
+type mapGraphDB map[string][]string
+
+func (m mapGraphDB) Nodes() ([]string, error) { return collectURLs(m, nil), nil }
+func (m mapGraphDB) Backlinks(url string) ([]string, error) {
+	return m[url], nil
+}
+
+type brokenGraphDB struct{ mapGraphDB }
+
+func (brokenGraphDB) Backlinks(string) ([]string, error) { return nil, errors.New("connection reset") }
+
+func TestCalculateFromDBMatchesCalculate(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	want := New().Calculate(backlinks, outlinks)
+	got, err := New().CalculateFromDB(mapGraphDB(backlinks))
+	if err != nil {
+		t.Fatalf("CalculateFromDB: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("result %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCalculateFromDBPropagatesErrors(t *testing.T) {
+	backlinks, _ := sampleGraph()
+	if _, err := New().CalculateFromDB(brokenGraphDB{mapGraphDB(backlinks)}); err == nil {
+		t.Error("expected backlink error to be returned")
+	}
+}
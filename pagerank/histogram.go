@@ -0,0 +1,91 @@
+package pagerank
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+const histogramBarWidth = 40
+
+type HistogramBucket struct {
+	LowerBound float64
+	UpperBound float64
+	Count      float64
+}
+
+// ComputeRankHistogram counts results into log-spaced buckets spanning the
+// smallest to the largest positive rank. The last bucket includes its upper
+// bound; non-positive ranks are counted in the first bucket.
+func ComputeRankHistogram(results []Result, buckets int) []HistogramBucket {
+	if buckets <= 0 || len(results) == 0 {
+		return nil
+	}
+
+	lo, hi := math.Inf(1), 0.0
+	for _, r := range results {
+		if r.Rank > 0 && r.Rank < lo {
+			lo = r.Rank
+		}
+		if r.Rank > hi {
+			hi = r.Rank
+		}
+	}
+	if math.IsInf(lo, 1) {
+		lo, hi = 1e-9, 1
+	}
+	if hi <= lo {
+		hi = lo * 2
+	}
+
+	logLo, logHi := math.Log(lo), math.Log(hi)
+	step := (logHi - logLo) / float64(buckets)
+	out := make([]HistogramBucket, buckets)
+	for i := range out {
+		out[i].LowerBound = math.Exp(logLo + step*float64(i))
+		out[i].UpperBound = math.Exp(logLo + step*float64(i+1))
+	}
+	out[0].LowerBound = lo
+	out[buckets-1].UpperBound = hi
+
+	for _, r := range results {
+		i := 0
+		if r.Rank > 0 {
+			i = int((math.Log(r.Rank) - logLo) / step)
+		}
+		if i < 0 {
+			i = 0
+		}
+		if i >= buckets {
+			i = buckets - 1
+		}
+		out[i].Count++
+	}
+	return out
+}
+
+// PrintHistogram is WriteHistogram without the error, for writers that
+// cannot fail, such as a bytes.Buffer.
+func PrintHistogram(w io.Writer, buckets []HistogramBucket) {
+	_ = WriteHistogram(w, buckets)
+}
+
+// WriteHistogram draws buckets as a horizontal bar chart.
+func WriteHistogram(w io.Writer, buckets []HistogramBucket) error {
+	var max float64
+	for _, b := range buckets {
+		max = math.Max(max, b.Count)
+	}
+	for _, b := range buckets {
+		bar := 0
+		if max > 0 {
+			bar = int(math.Round(b.Count / max * histogramBarWidth))
+		}
+		if _, err := fmt.Fprintf(w, "[%.2e, %.2e) | %-*s %d\n",
+			b.LowerBound, b.UpperBound, histogramBarWidth, strings.Repeat("#", bar), int(b.Count)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package pagerank
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestComputeRankHistogram(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(2000, 3, 1)
+	results := New().Calculate(backlinks, outlinks)
+
+	buckets := ComputeRankHistogram(results, 10)
+	if len(buckets) != 10 {
+		t.Fatalf("expected 10 buckets, got %d", len(buckets))
+	}
+	var total float64
+	for i, b := range buckets {
+		total += b.Count
+		if b.UpperBound <= b.LowerBound {
+			t.Errorf("bucket %d bounds not increasing: %v", i, b)
+		}
+		if i > 0 && b.LowerBound <= buckets[i-1].LowerBound {
+			t.Errorf("bucket %d lower bound %v not above previous %v", i, b.LowerBound, buckets[i-1].LowerBound)
+		}
+	}
+	if int(total) != len(results) {
+		t.Errorf("bucket counts sum to %v, want %d", total, len(results))
+	}
+	if buckets[0].Count <= buckets[9].Count {
+		t.Errorf("power-law graph should have more low-rank than high-rank pages: %v", buckets)
+	}
+}
+
+func TestComputeRankHistogramEqualRanks(t *testing.T) {
+	results := []Result{{URL: "a", Rank: 0.5}, {URL: "b", Rank: 0.5}}
+	buckets := ComputeRankHistogram(results, 3)
+	var total float64
+	for _, b := range buckets {
+		total += b.Count
+		if b.UpperBound <= b.LowerBound {
+			t.Errorf("bounds not increasing: %v", b)
+		}
+	}
+	if total != 2 {
+		t.Errorf("expected 2 counted results, got %v", total)
+	}
+}
+
+func TestWriteHistogram(t *testing.T) {
+	buckets := []HistogramBucket{
+		{LowerBound: 0.01, UpperBound: 0.1, Count: 4},
+		{LowerBound: 0.1, UpperBound: 1, Count: 1},
+	}
+	var buf bytes.Buffer
+	if err := WriteHistogram(&buf, buckets); err != nil {
+		t.Fatalf("WriteHistogram: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if strings.Count(lines[0], "#") != histogramBarWidth || strings.Count(lines[1], "#") != histogramBarWidth/4 {
+		t.Errorf("bars not scaled to the largest bucket:\n%s", buf.String())
+	}
+
+	var printed bytes.Buffer
+	PrintHistogram(&printed, buckets)
+	if printed.String() != buf.String() {
+		t.Errorf("PrintHistogram wrote\n%s\nwant\n%s", printed.String(), buf.String())
+	}
+}
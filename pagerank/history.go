@@ -0,0 +1,71 @@
+package pagerank
+
+// SetHistoryEnabled makes each calculation record every page's rank after
+// every iteration, readable afterwards through History. Only the focus
+// set is recorded if one is configured. Recording writes to the
+// calculator, so concurrent calculations on one calculator must not have
+// it enabled.
+func (c *Calculator) SetHistoryEnabled(enabled bool) *Calculator {
+	c.historyEnabled = enabled
+	if !enabled {
+		c.history = nil
+	}
+	return c
+}
+
+// SetFocusSet limits results, and the recorded history, to urls. The
+// whole graph is still ranked, so focus pages get exactly the ranks
+// Calculate would give them. Focus pages that are not in the graph are
+// left out. An empty set clears the focus.
+func (c *Calculator) SetFocusSet(urls []string) *Calculator {
+	c.focus = nil
+	if len(urls) > 0 {
+		c.focus = make(map[string]bool, len(urls))
+		for _, url := range urls {
+			c.focus[url] = true
+		}
+	}
+	return c
+}
+
+// History returns the rank of each recorded page after each iteration of
+// the most recent calculation, or nil if history is disabled.
+func (c *Calculator) History() map[string][]float64 {
+	return c.history
+}
+
+type historyRecorder struct {
+	pages  []int
+	series [][]float64
+}
+
+func (c *Calculator) newHistoryRecorder(g *linkIndex) *historyRecorder {
+	if !c.historyEnabled {
+		return nil
+	}
+	rec := &historyRecorder{}
+	for i, url := range g.urls {
+		if c.focus == nil || c.focus[url] {
+			rec.pages = append(rec.pages, i)
+		}
+	}
+	rec.series = make([][]float64, len(rec.pages))
+	for k := range rec.series {
+		rec.series[k] = make([]float64, 0, c.iterations)
+	}
+	return rec
+}
+
+func (rec *historyRecorder) byURL(urls []string) map[string][]float64 {
+	history := make(map[string][]float64, len(rec.pages))
+	for k, i := range rec.pages {
+		history[urls[i]] = rec.series[k]
+	}
+	return history
+}
+
+func recordHistory[F rankFloat](rec *historyRecorder, rank []F) {
+	for k, i := range rec.pages {
+		rec.series[k] = append(rec.series[k], float64(rank[i]))
+	}
+}
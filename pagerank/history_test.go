@@ -0,0 +1,57 @@
+package pagerank
+
+import "testing"
+
+func TestFocusSetMatchesFullCalculate(t *testing.T) {
+	backlinks, outlinks := syntheticGraph(500, 4, 9)
+	full := ScoreMap(New().Calculate(backlinks, outlinks))
+
+	focus := []string{"page-3", "page-77", "page-250", "page-499"}
+	calc := New().SetFocusSet(focus).SetHistoryEnabled(true)
+	got := calc.Calculate(backlinks, outlinks)
+	if len(got) != len(focus) {
+		t.Fatalf("expected %d results, got %d", len(focus), len(got))
+	}
+	for i, r := range got {
+		if r.Rank != full[r.URL] {
+			t.Errorf("%s: focus rank %v, full rank %v", r.URL, r.Rank, full[r.URL])
+		}
+		if i > 0 && got[i-1].Rank < r.Rank {
+			t.Errorf("results not sorted by rank: %v", got)
+		}
+	}
+
+	history := calc.History()
+	if len(history) != len(focus) {
+		t.Fatalf("expected history for %d focus pages, got %d", len(focus), len(history))
+	}
+	for _, r := range got {
+		series := history[r.URL]
+		if len(series) != calc.Iterations() {
+			t.Fatalf("%s: %d history points, want %d", r.URL, len(series), calc.Iterations())
+		}
+		if series[len(series)-1] != r.Rank {
+			t.Errorf("%s: last history point %v, final rank %v", r.URL, series[len(series)-1], r.Rank)
+		}
+	}
+
+	calc.SetFocusSet(nil).SetHistoryEnabled(false)
+	if got := calc.Calculate(backlinks, outlinks); len(got) != len(full) {
+		t.Errorf("cleared focus returned %d results, want %d", len(got), len(full))
+	}
+	if calc.History() != nil {
+		t.Error("history should be nil when disabled")
+	}
+}
+
+func TestHistoryWithoutFocusRecordsEveryPage(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calc := New().SetIterations(5).SetHistoryEnabled(true)
+	calc.Calculate(backlinks, outlinks)
+	if h := calc.History(); len(h) != 4 || len(h["page-a"]) != 5 {
+		t.Errorf("unexpected history %v", h)
+	}
+	if calc.Clone().History() != nil {
+		t.Error("Clone should not share history")
+	}
+}
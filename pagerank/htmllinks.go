@@ -0,0 +1,74 @@
+package pagerank
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/html"
+)
+
+// ExtractLinksFromHTML returns the absolute http(s) targets of every
+// <a href> in body, resolved against pageURL, in document order. Fragments
+// are dropped and duplicates removed.
+func ExtractLinksFromHTML(pageURL string, body io.Reader) ([]string, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: parse page url %q: %w", pageURL, err)
+	}
+	doc, err := html.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: parse html for %s: %w", pageURL, err)
+	}
+
+	var links []string
+	seen := make(map[string]bool)
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			for _, attr := range n.Attr {
+				if attr.Key != "href" {
+					continue
+				}
+				ref, err := url.Parse(attr.Val)
+				if err != nil {
+					continue
+				}
+				abs := base.ResolveReference(ref)
+				if abs.Scheme != "http" && abs.Scheme != "https" {
+					continue
+				}
+				abs.Fragment = ""
+				if s := abs.String(); !seen[s] {
+					seen[s] = true
+					links = append(links, s)
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+	return links, nil
+}
+
+// AddEdgesFromHTMLResponse adds an edge from the response's request URL to
+// every link found in its body. The body is read but not closed.
+func (g *Graph) AddEdgesFromHTMLResponse(resp *http.Response) error {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return errors.New("pagerank: response has no request URL")
+	}
+	source := resp.Request.URL.String()
+	links, err := ExtractLinksFromHTML(source, resp.Body)
+	if err != nil {
+		return err
+	}
+	g.AddNode(source)
+	for _, target := range links {
+		g.AddEdge(source, target)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package pagerank
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const samplePage = `<!doctype html>
+<html><body>
+  <a href="/about">About</a>
+  <a href="contact.html#form">Contact</a>
+  <a href="../up.html">Up</a>
+  <a href="https://other.example.org/page">Other</a>
+  <a href="mailto:team@example.com">Mail</a>
+  <a name="no-href">Anchor</a>
+  <p><a href="/about">About again</a></p>
+</body></html>`
+
+func TestExtractLinksFromHTML(t *testing.T) {
+	links, err := ExtractLinksFromHTML("https://example.com/docs/index.html", strings.NewReader(samplePage))
+	if err != nil {
+		t.Fatalf("ExtractLinksFromHTML: %v", err)
+	}
+	want := []string{
+		"https://example.com/about",
+		"https://example.com/docs/contact.html",
+		"https://example.com/up.html",
+		"https://other.example.org/page",
+	}
+	if len(links) != len(want) {
+		t.Fatalf("got %v, want %v", links, want)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("link %d = %s, want %s", i, links[i], want[i])
+		}
+	}
+}
+
+func TestExtractLinksFromHTMLBadBase(t *testing.T) {
+	if _, err := ExtractLinksFromHTML("://bad", strings.NewReader(samplePage)); err == nil {
+		t.Error("expected error for invalid page URL")
+	}
+}
+
+func TestAddEdgesFromHTMLResponse(t *testing.T) {
+	page, _ := url.Parse("https://example.com/docs/index.html")
+	resp := &http.Response{
+		Request: &http.Request{URL: page},
+		Body:    io.NopCloser(strings.NewReader(samplePage)),
+	}
+
+	g := NewGraph()
+	if err := g.AddEdgesFromHTMLResponse(resp); err != nil {
+		t.Fatalf("AddEdgesFromHTMLResponse: %v", err)
+	}
+	if g.EdgeCount() != 4 || g.NodeCount() != 5 {
+		t.Errorf("expected 5 nodes and 4 edges, got %d and %d", g.NodeCount(), g.EdgeCount())
+	}
+	_, outlinks := g.Build()
+	if outlinks[page.String()] != 4 {
+		t.Errorf("expected page to have 4 outlinks, got %d", outlinks[page.String()])
+	}
+}
@@ -0,0 +1,116 @@
+package pagerank
+
+import (
+	"math"
+	"math/rand"
+)
+
+type NodePosition struct {
+	X, Y float64
+}
+
+// ComputeForceDirectedLayout places every page with the Fruchterman-Reingold
+// algorithm: linked pages attract, all pairs repel, and the step size
+// cools linearly over iterations. Higher-ranked pages repel harder, leaving
+// room to draw them larger. Each iteration is quadratic in the number of
+// pages, so this is meant for graphs small enough to look at.
+func ComputeForceDirectedLayout(backlinks map[string][]string, results []Result, iterations int, seed int64) map[string]NodePosition {
+	urls := exportURLs(backlinks, results)
+	n := len(urls)
+	if n == 0 {
+		return map[string]NodePosition{}
+	}
+	index := make(map[string]int, n)
+	for i, url := range urls {
+		index[url] = i
+	}
+
+	size := layoutSizes(urls, results)
+	// Pages start spread over an area of n, so the ideal distance between
+	// neighbours, sqrt(area/n), is 1.
+	side := math.Sqrt(float64(n))
+	const k = 1.0
+	rng := rand.New(rand.NewSource(seed))
+	pos := make([]NodePosition, n)
+	for i := range pos {
+		pos[i] = NodePosition{X: rng.Float64() * side, Y: rng.Float64() * side}
+	}
+
+	var links [][2]int
+	for _, target := range collectURLs(backlinks, nil) {
+		for _, src := range backlinks[target] {
+			if s, t := index[src], index[target]; s != t {
+				links = append(links, [2]int{s, t})
+			}
+		}
+	}
+
+	disp := make([]NodePosition, n)
+	for it := 0; it < iterations; it++ {
+		temp := side / 10 * (1 - float64(it)/float64(iterations))
+		for i := range disp {
+			disp[i] = NodePosition{}
+		}
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				dx, dy := pos[i].X-pos[j].X, pos[i].Y-pos[j].Y
+				d := math.Hypot(dx, dy)
+				if d < 1e-9 {
+					// Coincident pages get pushed apart in a random direction.
+					angle := rng.Float64() * 2 * math.Pi
+					dx, dy, d = math.Cos(angle)*1e-3, math.Sin(angle)*1e-3, 1e-3
+				}
+				f := k * k / d * (1 + size[i]) * (1 + size[j])
+				disp[i].X += dx / d * f
+				disp[i].Y += dy / d * f
+				disp[j].X -= dx / d * f
+				disp[j].Y -= dy / d * f
+			}
+		}
+		for _, l := range links {
+			i, j := l[0], l[1]
+			dx, dy := pos[i].X-pos[j].X, pos[i].Y-pos[j].Y
+			d := math.Hypot(dx, dy)
+			if d < 1e-9 {
+				continue
+			}
+			f := d * d / k
+			disp[i].X -= dx / d * f
+			disp[i].Y -= dy / d * f
+			disp[j].X += dx / d * f
+			disp[j].Y += dy / d * f
+		}
+		for i := range pos {
+			d := math.Hypot(disp[i].X, disp[i].Y)
+			if d < 1e-9 {
+				continue
+			}
+			step := math.Min(d, temp)
+			pos[i].X += disp[i].X / d * step
+			pos[i].Y += disp[i].Y / d * step
+		}
+	}
+
+	out := make(map[string]NodePosition, n)
+	for i, url := range urls {
+		out[url] = pos[i]
+	}
+	return out
+}
+
+// layoutSizes scales each page's rank to [0, 1] against the top rank.
+func layoutSizes(urls []string, results []Result) []float64 {
+	rank := ScoreMap(results)
+	var max float64
+	for _, r := range results {
+		max = math.Max(max, r.Rank)
+	}
+	size := make([]float64, len(urls))
+	if max <= 0 {
+		return size
+	}
+	for i, url := range urls {
+		size[i] = rank[url] / max
+	}
+	return size
+}
@@ -0,0 +1,32 @@
+package pagerank
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeForceDirectedLayout(t *testing.T) {
+	backlinks, outlinks := syntheticGraph(60, 3, 5)
+	results := New().Calculate(backlinks, outlinks)
+	layout := ComputeForceDirectedLayout(backlinks, results, 100, 1)
+	if len(layout) != len(results) {
+		t.Fatalf("expected %d positions, got %d", len(results), len(layout))
+	}
+	seen := make(map[NodePosition]string, len(layout))
+	for url, p := range layout {
+		if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) {
+			t.Fatalf("%s has a non-finite position %v", url, p)
+		}
+		if other, dup := seen[p]; dup {
+			t.Fatalf("%s and %s share position %v", url, other, p)
+		}
+		seen[p] = url
+	}
+
+	again := ComputeForceDirectedLayout(backlinks, results, 100, 1)
+	for url, p := range layout {
+		if again[url] != p {
+			t.Fatalf("layout is not deterministic for a fixed seed: %s %v vs %v", url, p, again[url])
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package pagerank
+
+import "math"
+
+// learnIterations is how many power-iteration steps LearnEdgeWeights takes
+// for each rank and adjoint solve.
+const learnIterations = 50
+
+// learnDamping is the damping factor of the model LearnEdgeWeights fits,
+// New's default.
+const learnDamping = 0.85
+
+// LearnEdgeWeights fits a weight for every link so that PageRank with
+// those weights comes closer to groundTruth, by gradient descent on the
+// sum of squared rank differences. Each page's weights are a softmax over
+// its distinct link targets, starting from the equal split, and the
+// gradient comes from the adjoint of the PageRank fixed point. Ground-truth
+// ranks are rescaled to the same total as the unweighted ranks; pages
+// missing from groundTruth do not count towards the loss. The result maps
+// source to target to weight, each source's weights summing to 1, ready
+// for SetOutlinkNormalizer.
+//
+// The model is fixed rather than taken from a Calculator: damping 0.85,
+// uniform teleportation, and rank that reaches a page without links is
+// dropped rather than spread. Weights learned here only approximately fit
+// a Calculator configured differently.
+//
+// Rank differences are small numbers, so learningRate usually needs to be
+// large, on the order of the number of pages squared.
+func LearnEdgeWeights(backlinks map[string][]string, outlinksCount map[string]int, groundTruth []Result, learningRate float64, epochs int) map[string]map[string]float64 {
+	urls := collectURLs(backlinks, outlinksCount)
+	n := len(urls)
+	index := make(map[string]int, n)
+	for i, url := range urls {
+		index[url] = i
+	}
+	// targets[s] lists the distinct pages s links to; theta[s][k] is the
+	// logit of the link to targets[s][k].
+	targets := make([][]int, n)
+	for src, dsts := range forwardLinks(backlinks) {
+		s := index[src]
+		for _, dst := range dedupeSorted(dsts) {
+			targets[s] = append(targets[s], index[dst])
+		}
+	}
+	theta := make([][]float64, n)
+	share := make([][]float64, n)
+	for s := range targets {
+		theta[s] = make([]float64, len(targets[s]))
+		share[s] = make([]float64, len(targets[s]))
+	}
+
+	const damping = learnDamping
+	softmax := func() {
+		for s, logits := range theta {
+			max := math.Inf(-1)
+			for _, l := range logits {
+				max = math.Max(max, l)
+			}
+			var sum float64
+			for k, l := range logits {
+				share[s][k] = math.Exp(l - max)
+				sum += share[s][k]
+			}
+			for k := range share[s] {
+				share[s][k] /= sum
+			}
+		}
+	}
+	// solve returns x = base + damping * P x, or with transpose set
+	// x = base + damping * P^T x, where P[t][s] = share of s's link to t.
+	solve := func(base []float64, transpose bool) []float64 {
+		x := append([]float64(nil), base...)
+		next := make([]float64, n)
+		for it := 0; it < learnIterations; it++ {
+			copy(next, base)
+			for s, ts := range targets {
+				for k, t := range ts {
+					if transpose {
+						next[s] += damping * share[s][k] * x[t]
+					} else {
+						next[t] += damping * share[s][k] * x[s]
+					}
+				}
+			}
+			x, next = next, x
+		}
+		return x
+	}
+
+	softmax()
+	teleport := make([]float64, n)
+	for i := range teleport {
+		teleport[i] = (1 - damping) / float64(n)
+	}
+	rank := solve(teleport, false)
+
+	truth := make([]float64, n)
+	known := make([]bool, n)
+	var truthSum, rankSum float64
+	for _, r := range groundTruth {
+		if i, ok := index[r.URL]; ok && !known[i] {
+			truth[i], known[i] = r.Rank, true
+			truthSum += r.Rank
+			rankSum += rank[i]
+		}
+	}
+	if truthSum > 0 {
+		for i := range truth {
+			truth[i] *= rankSum / truthSum
+		}
+	}
+
+	residual := make([]float64, n)
+	for epoch := 0; epoch < epochs; epoch++ {
+		for i := range residual {
+			residual[i] = 0
+			if known[i] {
+				residual[i] = 2 * (rank[i] - truth[i])
+			}
+		}
+		adjoint := solve(residual, true)
+		// dL/dP[t][s] = damping * adjoint[t] * rank[s], pushed through the
+		// softmax of s's row.
+		for s, ts := range targets {
+			var mean float64
+			for k, t := range ts {
+				mean += share[s][k] * adjoint[t]
+			}
+			for k, t := range ts {
+				grad := damping * rank[s] * share[s][k] * (adjoint[t] - mean)
+				theta[s][k] -= learningRate * grad
+			}
+		}
+		softmax()
+		rank = solve(teleport, false)
+	}
+
+	weights := make(map[string]map[string]float64)
+	for s, ts := range targets {
+		if len(ts) == 0 {
+			continue
+		}
+		row := make(map[string]float64, len(ts))
+		for k, t := range ts {
+			row[urls[t]] = share[s][k]
+		}
+		weights[urls[s]] = row
+	}
+	return weights
+}
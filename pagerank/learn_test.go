@@ -0,0 +1,69 @@
+package pagerank
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// pearson is the correlation of two rankings over the pages in both.
+func pearson(a, b map[string]float64) float64 {
+	var xs, ys []float64
+	for url, x := range a {
+		if y, ok := b[url]; ok {
+			xs, ys = append(xs, x), append(ys, y)
+		}
+	}
+	var mx, my float64
+	for i := range xs {
+		mx += xs[i]
+		my += ys[i]
+	}
+	mx /= float64(len(xs))
+	my /= float64(len(ys))
+	var sxy, sxx, syy float64
+	for i := range xs {
+		sxy += (xs[i] - mx) * (ys[i] - my)
+		sxx += (xs[i] - mx) * (xs[i] - mx)
+		syy += (ys[i] - my) * (ys[i] - my)
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+func weightsNormalizer(weights map[string]map[string]float64) OutlinkNormalizer {
+	return func(source string, _ []string, _ map[string]int) map[string]float64 {
+		return weights[source]
+	}
+}
+
+func TestLearnEdgeWeightsImprovesCorrelation(t *testing.T) {
+	backlinks, outlinks := syntheticGraph(40, 4, 3)
+	// The ground truth comes from hidden random link weights.
+	rng := rand.New(rand.NewSource(9))
+	hidden := make(map[string]map[string]float64)
+	for src, dsts := range forwardLinks(backlinks) {
+		hidden[src] = make(map[string]float64)
+		for _, dst := range dsts {
+			hidden[src][dst] = rng.Float64() * rng.Float64()
+		}
+	}
+	truth := New().SetOutlinkNormalizer(weightsNormalizer(hidden)).Calculate(backlinks, outlinks)
+	truthMap := ScoreMap(truth)
+
+	baseline := pearson(ScoreMap(New().Calculate(backlinks, outlinks)), truthMap)
+	learned := LearnEdgeWeights(backlinks, outlinks, truth, 1000, 100)
+	for src, row := range learned {
+		var sum float64
+		for _, w := range row {
+			sum += w
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Fatalf("weights out of %s sum to %v", src, sum)
+		}
+	}
+	after := pearson(ScoreMap(New().SetOutlinkNormalizer(weightsNormalizer(learned)).Calculate(backlinks, outlinks)), truthMap)
+	t.Logf("correlation %.4f -> %.4f", baseline, after)
+	if after <= baseline {
+		t.Errorf("learning did not improve correlation: %.4f -> %.4f", baseline, after)
+	}
+}
@@ -0,0 +1,98 @@
+package pagerank
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// LinkType is how a link was placed, which decides how much rank it
+// carries; see SetLinkClassifier.
+type LinkType int
+
+const (
+	Unknown LinkType = iota
+	Editorial
+	Paid
+	Nofollow
+)
+
+func (lt LinkType) String() string {
+	switch lt {
+	case Editorial:
+		return "editorial"
+	case Paid:
+		return "paid"
+	case Nofollow:
+		return "nofollow"
+	}
+	return "unknown"
+}
+
+// weight is the fraction of a link's usual share that it passes on.
+func (lt LinkType) weight() float64 {
+	switch lt {
+	case Editorial:
+		return 1
+	case Paid, Nofollow:
+		return 0
+	}
+	return 0.5
+}
+
+type LinkClassifier interface {
+	Classify(source, target string) LinkType
+}
+
+type linkRule struct {
+	re *regexp.Regexp
+	lt LinkType
+}
+
+// RegexLinkClassifier classifies links by their target URL. Rules are
+// tried in the order they were added and the first match wins; links no
+// rule matches are Unknown.
+type RegexLinkClassifier struct {
+	rules []linkRule
+}
+
+func NewRegexLinkClassifier() *RegexLinkClassifier {
+	return &RegexLinkClassifier{}
+}
+
+// AddRule classifies links whose target matches pattern as lt.
+func (rc *RegexLinkClassifier) AddRule(pattern string, lt LinkType) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("pagerank: link rule %q: %w", pattern, err)
+	}
+	rc.rules = append(rc.rules, linkRule{re, lt})
+	return nil
+}
+
+func (rc *RegexLinkClassifier) Classify(source, target string) LinkType {
+	for _, r := range rc.rules {
+		if r.re.MatchString(target) {
+			return r.lt
+		}
+	}
+	return Unknown
+}
+
+// applyLinkClassifier scales every link's share by the weight of its
+// type, filling in the default 1/out shares first if no normalizer did.
+func (c *Calculator) applyLinkClassifier(g *linkIndex) {
+	if g.weights == nil {
+		g.weights = make([][]float64, len(g.urls))
+		for i, sources := range g.sources {
+			g.weights[i] = make([]float64, len(sources))
+			for k, src := range sources {
+				g.weights[i][k] = 1 / g.out[src]
+			}
+		}
+	}
+	for i, sources := range g.sources {
+		for k, src := range sources {
+			g.weights[i][k] *= c.classifier.Classify(g.urls[src], g.urls[i]).weight()
+		}
+	}
+}
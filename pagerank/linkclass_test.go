@@ -0,0 +1,64 @@
+package pagerank
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRegexLinkClassifier(t *testing.T) {
+	rc := NewRegexLinkClassifier()
+	if err := rc.AddRule(`[?&]ref=affiliate`, Paid); err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.AddRule(`^https://news\.example/`, Editorial); err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.AddRule(`(`, Paid); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+	cases := map[string]LinkType{
+		"https://shop.example/item?ref=affiliate":  Paid,
+		"https://news.example/story":               Editorial,
+		"https://news.example/story?ref=affiliate": Paid,
+		"https://blog.example/post":                Unknown,
+	}
+	for target, want := range cases {
+		if got := rc.Classify("https://src.example/", target); got != want {
+			t.Errorf("Classify(%s) = %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestLinkClassifierPaidInlinksCarryNoRank(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	backlinks["sponsor"] = []string{"page-a", "page-b"}
+	outlinks["page-a"]++
+	outlinks["page-b"]++
+
+	rc := NewRegexLinkClassifier()
+	rc.AddRule(`^sponsor$`, Paid)
+	rc.AddRule(`.`, Editorial)
+	calc := New().SetLinkClassifier(rc)
+	ranks := ScoreMap(calc.Calculate(backlinks, outlinks))
+
+	base := (1 - calc.Damping()) / float64(len(ranks))
+	if math.Abs(ranks["sponsor"]-base) > 1e-12 {
+		t.Errorf("sponsor rank %v, want the teleport base %v", ranks["sponsor"], base)
+	}
+	plain := ScoreMap(New().Calculate(backlinks, outlinks))
+	if ranks["sponsor"] >= plain["sponsor"] {
+		t.Errorf("classifier did not lower the sponsor's rank: %v vs %v", ranks["sponsor"], plain["sponsor"])
+	}
+}
+
+func TestLinkClassifierUnknownHalvesShare(t *testing.T) {
+	backlinks := map[string][]string{"b": {"a"}}
+	outlinks := map[string]int{"a": 1, "b": 0}
+	calc := New().SetLinkClassifier(NewRegexLinkClassifier()).SetIterations(1)
+	ranks := ScoreMap(calc.Calculate(backlinks, outlinks))
+	// One step from 1/2 each: b gets the base plus half of a's damped rank.
+	want := (1-calc.Damping())/2 + calc.Damping()*0.5*0.5
+	if math.Abs(ranks["b"]-want) > 1e-12 {
+		t.Errorf("b = %v, want %v", ranks["b"], want)
+	}
+}
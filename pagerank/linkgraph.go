@@ -0,0 +1,131 @@
+package pagerank
+
+import "fmt"
+
+type edge struct {
+	source, target string
+}
+
+// Graph incrementally assembles a link graph and produces the backlinks and
+// outlink-count maps that Calculate consumes. Duplicate edges are ignored.
+type Graph struct {
+	nodes     map[string]bool
+	edges     map[edge]bool
+	backlinks map[string][]string
+	outlinks  map[string]int
+	robots    *RobotsFilter
+}
+
+func NewGraph() *Graph {
+	return &Graph{
+		nodes:     make(map[string]bool),
+		edges:     make(map[edge]bool),
+		backlinks: make(map[string][]string),
+		outlinks:  make(map[string]int),
+	}
+}
+
+// GraphFromResults returns a graph containing every result URL as an
+// isolated node.
+func GraphFromResults(results []Result) *Graph {
+	g := NewGraph()
+	for _, r := range results {
+		g.AddNode(r.URL)
+	}
+	return g
+}
+
+func (g *Graph) AddNode(url string) *Graph {
+	if !g.nodes[url] {
+		g.nodes[url] = true
+		g.outlinks[url] = 0
+	}
+	return g
+}
+
+// WithRobotsFilter makes AddEdge drop links that rf disallows.
+func (g *Graph) WithRobotsFilter(rf *RobotsFilter) *Graph {
+	g.robots = rf
+	return g
+}
+
+func (g *Graph) AddEdge(source, target string) *Graph {
+	e := edge{source, target}
+	if g.edges[e] {
+		return g
+	}
+	if g.robots != nil && !g.robots.Allow(source, target) {
+		return g
+	}
+	g.AddNode(source).AddNode(target)
+	g.edges[e] = true
+	g.backlinks[target] = append(g.backlinks[target], source)
+	g.outlinks[source]++
+	return g
+}
+
+// AddEdgeIncremental adds a single edge, updating the backlinks and
+// outlink count for just that edge. It is equivalent to AddEdge, which
+// already maintains both maps in place; it exists to pair with
+// RemoveEdgeIncremental.
+func (g *Graph) AddEdgeIncremental(source, target string) {
+	g.AddEdge(source, target)
+}
+
+// RemoveEdgeIncremental removes one edge and decrements the source's
+// outlink count. Both endpoints stay in the graph.
+func (g *Graph) RemoveEdgeIncremental(source, target string) error {
+	e := edge{source, target}
+	if !g.edges[e] {
+		return fmt.Errorf("pagerank: no edge %s -> %s", source, target)
+	}
+	delete(g.edges, e)
+	g.outlinks[source]--
+
+	sources := g.backlinks[target]
+	for i, src := range sources {
+		if src == source {
+			sources = append(sources[:i], sources[i+1:]...)
+			break
+		}
+	}
+	if len(sources) == 0 {
+		delete(g.backlinks, target)
+	} else {
+		g.backlinks[target] = sources
+	}
+	return nil
+}
+
+// RemoveNode removes url and every link to or from it. Pages that only
+// linked to url stay in the graph with one outlink fewer.
+func (g *Graph) RemoveNode(url string) error {
+	if !g.nodes[url] {
+		return fmt.Errorf("pagerank: no node %s", url)
+	}
+	for e := range g.edges {
+		if e.source == url || e.target == url {
+			g.RemoveEdgeIncremental(e.source, e.target)
+		}
+	}
+	delete(g.nodes, url)
+	delete(g.outlinks, url)
+	return nil
+}
+
+func (g *Graph) NodeCount() int { return len(g.nodes) }
+func (g *Graph) EdgeCount() int { return len(g.edges) }
+
+// Build returns copies of the graph as backlinks and outlink counts. Every
+// node appears in the outlink counts, with zero if it links nowhere.
+func (g *Graph) Build() (map[string][]string, map[string]int) {
+	backlinks := make(map[string][]string, len(g.backlinks))
+	for target, sources := range g.backlinks {
+		backlinks[target] = append([]string(nil), sources...)
+	}
+	outlinks := make(map[string]int, len(g.outlinks))
+	for url, n := range g.outlinks {
+		outlinks[url] = n
+	}
+	return backlinks, outlinks
+}
@@ -0,0 +1,185 @@
+package pagerank
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphBuildMatchesSample(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	g := NewGraph()
+	for target, sources := range backlinks {
+		for _, src := range sources {
+			g.AddEdge(src, target)
+		}
+	}
+	g.AddEdge("page-a", "page-c")
+
+	gotBacklinks, gotOutlinks := g.Build()
+	if g.NodeCount() != 4 || g.EdgeCount() != 8 {
+		t.Fatalf("expected 4 nodes and 8 edges, got %d and %d", g.NodeCount(), g.EdgeCount())
+	}
+	for url, n := range outlinks {
+		if gotOutlinks[url] != n {
+			t.Errorf("outlinks[%s] = %d, want %d", url, gotOutlinks[url], n)
+		}
+	}
+	for url, sources := range backlinks {
+		if len(gotBacklinks[url]) != len(sources) {
+			t.Errorf("backlinks[%s] = %v, want %v", url, gotBacklinks[url], sources)
+		}
+	}
+}
+
+func TestGraphFromResults(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	results := New().Calculate(backlinks, outlinks)
+
+	g := GraphFromResults(results)
+	gotBacklinks, gotOutlinks := g.Build()
+	if g.NodeCount() != len(results) {
+		t.Errorf("expected %d nodes, got %d", len(results), g.NodeCount())
+	}
+	if g.EdgeCount() != 0 || len(gotBacklinks) != 0 {
+		t.Errorf("expected no edges, got %d", g.EdgeCount())
+	}
+	for _, r := range results {
+		if n, ok := gotOutlinks[r.URL]; !ok || n != 0 {
+			t.Errorf("node %s should be present with zero outlinks", r.URL)
+		}
+	}
+
+	g.AddEdge("page-a", "expansion-1")
+	if g.NodeCount() != len(results)+1 || g.EdgeCount() != 1 {
+		t.Errorf("AddEdge after GraphFromResults should extend the graph")
+	}
+}
+
+func TestIncrementalEdgesMatchRebuild(t *testing.T) {
+	ops := []struct {
+		add            bool
+		source, target string
+	}{
+		{true, "a", "b"},
+		{true, "a", "c"},
+		{true, "b", "c"},
+		{true, "c", "a"},
+		{false, "a", "c"},
+		{true, "d", "a"},
+		{true, "a", "d"},
+		{false, "b", "c"},
+		{true, "b", "a"},
+	}
+
+	g := NewGraph()
+	final := map[edge]bool{}
+	for _, op := range ops {
+		if op.add {
+			g.AddEdgeIncremental(op.source, op.target)
+			final[edge{op.source, op.target}] = true
+		} else {
+			if err := g.RemoveEdgeIncremental(op.source, op.target); err != nil {
+				t.Fatalf("remove %s -> %s: %v", op.source, op.target, err)
+			}
+			delete(final, edge{op.source, op.target})
+		}
+	}
+
+	fresh := NewGraph()
+	for _, op := range ops {
+		fresh.AddNode(op.source).AddNode(op.target)
+	}
+	for e := range final {
+		fresh.AddEdge(e.source, e.target)
+	}
+
+	gotBack, gotOut := g.Build()
+	wantBack, wantOut := fresh.Build()
+	if len(gotOut) != len(wantOut) {
+		t.Fatalf("outlinks = %v, want %v", gotOut, wantOut)
+	}
+	for url, n := range wantOut {
+		if gotOut[url] != n {
+			t.Errorf("outlinks[%s] = %d, want %d", url, gotOut[url], n)
+		}
+	}
+	if len(gotBack) != len(wantBack) {
+		t.Fatalf("backlinks = %v, want %v", gotBack, wantBack)
+	}
+	for url, sources := range wantBack {
+		got := map[string]bool{}
+		for _, src := range gotBack[url] {
+			got[src] = true
+		}
+		if len(got) != len(sources) {
+			t.Errorf("backlinks[%s] = %v, want %v", url, gotBack[url], sources)
+		}
+		for _, src := range sources {
+			if !got[src] {
+				t.Errorf("backlinks[%s] missing %s", url, src)
+			}
+		}
+	}
+}
+
+func TestRemoveEdgeIncrementalMissing(t *testing.T) {
+	g := NewGraph().AddEdge("a", "b")
+	if err := g.RemoveEdgeIncremental("b", "a"); err == nil {
+		t.Error("expected error removing an edge that does not exist")
+	}
+	if err := g.RemoveEdgeIncremental("a", "b"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := g.RemoveEdgeIncremental("a", "b"); err == nil {
+		t.Error("expected error removing the same edge twice")
+	}
+	if g.NodeCount() != 2 || g.EdgeCount() != 0 {
+		t.Errorf("expected 2 nodes and no edges, got %d and %d", g.NodeCount(), g.EdgeCount())
+	}
+}
+
+func TestRemoveNode(t *testing.T) {
+	g := NewGraph()
+	backlinks, _ := sampleGraph()
+	for target, sources := range backlinks {
+		for _, src := range sources {
+			g.AddEdge(src, target)
+		}
+	}
+	g.AddEdge("page-c", "page-c")
+	nodes := g.NodeCount()
+	if err := g.RemoveNode("page-c"); err != nil {
+		t.Fatalf("RemoveNode: %v", err)
+	}
+	if g.NodeCount() != nodes-1 {
+		t.Errorf("NodeCount = %d, want %d", g.NodeCount(), nodes-1)
+	}
+
+	gotBack, gotOut := g.Build()
+	if _, ok := gotOut["page-c"]; ok {
+		t.Error("page-c still has an outlink count")
+	}
+	if _, ok := gotBack["page-c"]; ok {
+		t.Error("page-c still has backlinks")
+	}
+	links := 0
+	for target, sources := range gotBack {
+		for _, src := range sources {
+			if src == "page-c" {
+				t.Errorf("%s still lists page-c as a backlink", target)
+			}
+			links++
+		}
+	}
+	// page-c linked to a, b and d and was linked from a and d.
+	if want := map[string]int{"page-a": 1, "page-b": 2, "page-d": 0}; !reflect.DeepEqual(gotOut, want) {
+		t.Errorf("outlinks = %v, want %v", gotOut, want)
+	}
+	if links != g.EdgeCount() || links != 3 {
+		t.Errorf("%d links built, EdgeCount %d, want 3", links, g.EdgeCount())
+	}
+
+	if err := g.RemoveNode("page-c"); err == nil {
+		t.Error("expected an error removing page-c twice")
+	}
+}
@@ -0,0 +1,74 @@
+package pagerank
+
+import "sync"
+
+// partialRanks is one map task's output: the new ranks of pages lo up to
+// lo+len(ranks).
+type partialRanks struct {
+	lo    int
+	ranks []float64
+}
+
+// MapReducePageRank computes the same ranks as Calculate in map-reduce
+// style. Each iteration, the pages are split into workers ranges; a map
+// task per range sums the rank flowing into each of its pages from the
+// previous iteration's vector, which no task modifies, and sends the sums
+// back on a channel. The reduce step adds the teleport share and
+// assembles the next vector. Damping and iteration values that New's
+// setters would reject fall back to the defaults.
+func MapReducePageRank(backlinks map[string][]string, outlinksCount map[string]int, damping float64, iterations, workers int) []Result {
+	c := New().SetDamping(damping).SetIterations(iterations)
+	g := buildLinkIndex(backlinks, outlinksCount)
+	total := len(g.urls)
+	if total == 0 {
+		return []Result{}
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > total {
+		workers = total
+	}
+	chunk := (total + workers - 1) / workers
+
+	rank := make([]float64, total)
+	for i := range rank {
+		rank[i] = 1.0 / float64(total)
+	}
+	teleport := c.teleportVector(g.urls)
+
+	for it := 0; it < c.iterations; it++ {
+		partials := make(chan partialRanks, workers)
+		var wg sync.WaitGroup
+		for lo := 0; lo < total; lo += chunk {
+			hi := lo + chunk
+			if hi > total {
+				hi = total
+			}
+			wg.Add(1)
+			go func(prev []float64, lo, hi int) {
+				defer wg.Done()
+				sums := make([]float64, hi-lo)
+				for i := lo; i < hi; i++ {
+					for _, src := range g.sources[i] {
+						sums[i-lo] += prev[src] / g.out[src]
+					}
+				}
+				partials <- partialRanks{lo, sums}
+			}(rank, lo, hi)
+		}
+		go func() {
+			wg.Wait()
+			close(partials)
+		}()
+
+		next := make([]float64, total)
+		for p := range partials {
+			for k, sum := range p.ranks {
+				next[p.lo+k] = teleport[p.lo+k] + c.damping*sum
+			}
+		}
+		rank = next
+	}
+	return resultsOf(g, rank, nil)
+}
@@ -0,0 +1,34 @@
+package pagerank
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMapReducePageRankMatchesCalculate(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(500, 3, 4)
+	want := New().SetDamping(0.8).SetIterations(30).Calculate(backlinks, outlinks)
+	for _, workers := range []int{1, 3, 8, 1000} {
+		got := MapReducePageRank(backlinks, outlinks, 0.8, 30, workers)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%d workers: results differ from Calculate", workers)
+		}
+	}
+	if got := MapReducePageRank(nil, nil, 0.85, 10, 4); len(got) != 0 {
+		t.Errorf("empty graph gave %v", got)
+	}
+}
+
+func BenchmarkMapReducePageRank(b *testing.B) {
+	backlinks, outlinks := GeneratePowerLawGraph(100_000, benchDegree, 1)
+	edges := int64(EdgeCount(backlinks))
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.SetBytes(edges)
+			for i := 0; i < b.N; i++ {
+				MapReducePageRank(backlinks, outlinks, 0.85, benchIterations, workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package pagerank
+
+import "fmt"
+
+// maxMatrixPages bounds TransitionMatrix, whose result grows with the
+// square of the page count.
+const maxMatrixPages = 10000
+
+// TransitionMatrix returns the column-stochastic matrix of one iteration,
+// teleportation included: column j holds where page j's rank goes, and
+// urlOrder names the rows and columns. A page with no outlinks in the
+// graph gets a uniform column, whereas Calculate lets its rank leak
+// away, so the two agree only when every page links somewhere. Graphs of
+// more than 10000 pages are an error.
+func (c *Calculator) TransitionMatrix(backlinks map[string][]string, outlinksCount map[string]int) ([][]float64, []string, error) {
+	g, err := c.index(backlinks, outlinksCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := len(g.urls)
+	if n > maxMatrixPages {
+		return nil, nil, fmt.Errorf("pagerank: transition matrix for %d pages exceeds the limit of %d", n, maxMatrixPages)
+	}
+
+	links := make([][]float64, n)
+	for i := range links {
+		links[i] = make([]float64, n)
+	}
+	for i, sources := range g.sources {
+		for k, src := range sources {
+			if g.weights != nil {
+				links[i][src] += g.weights[i][k]
+			} else {
+				links[i][src] += 1 / g.out[src]
+			}
+		}
+	}
+
+	teleport := c.teleportVector(g.urls)
+	matrix := make([][]float64, n)
+	for i := range matrix {
+		matrix[i] = make([]float64, n)
+	}
+	for j := 0; j < n; j++ {
+		var sum float64
+		for i := 0; i < n; i++ {
+			sum += links[i][j]
+		}
+		for i := 0; i < n; i++ {
+			p := links[i][j]
+			if sum == 0 {
+				p = 1 / float64(n)
+			}
+			matrix[i][j] = teleport[i] + c.damping*p
+		}
+	}
+	return matrix, g.urls, nil
+}
@@ -0,0 +1,65 @@
+package pagerank
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestTransitionMatrixColumnsSumToOne(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(60, 3, 4)
+	matrix, urls, err := New().TransitionMatrix(backlinks, outlinks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matrix) != len(urls) {
+		t.Fatalf("matrix has %d rows for %d pages", len(matrix), len(urls))
+	}
+	for j := range urls {
+		var sum float64
+		for i := range matrix {
+			sum += matrix[i][j]
+		}
+		if math.Abs(sum-1) > 1e-12 {
+			t.Errorf("column %s sums to %v", urls[j], sum)
+		}
+	}
+}
+
+func TestTransitionMatrixPowerMatchesCalculate(t *testing.T) {
+	backlinks, outlinks := syntheticGraph(80, 3, 6)
+	calc := New()
+	matrix, urls, err := calc.TransitionMatrix(backlinks, outlinks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rank := make([]float64, len(urls))
+	for i := range rank {
+		rank[i] = 1 / float64(len(urls))
+	}
+	for it := 0; it < calc.Iterations(); it++ {
+		next := make([]float64, len(rank))
+		for i, row := range matrix {
+			for j, p := range row {
+				next[i] += p * rank[j]
+			}
+		}
+		rank = next
+	}
+	want := ScoreMap(calc.Calculate(backlinks, outlinks))
+	for i, url := range urls {
+		if math.Abs(rank[i]-want[url]) > 1e-12 {
+			t.Errorf("%s: matrix power gives %v, Calculate %v", url, rank[i], want[url])
+		}
+	}
+}
+
+func TestTransitionMatrixTooLarge(t *testing.T) {
+	outlinks := make(map[string]int, maxMatrixPages+1)
+	for i := 0; i <= maxMatrixPages; i++ {
+		outlinks[fmt.Sprintf("p%d", i)] = 0
+	}
+	if _, _, err := New().TransitionMatrix(nil, outlinks); err == nil {
+		t.Error("expected an error above the page limit")
+	}
+}
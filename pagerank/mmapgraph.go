@@ -0,0 +1,200 @@
+package pagerank
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// The memory-mapped graph format, all integers little-endian:
+//
+//	magic       8 bytes, "GGLGRPH1"
+//	nodes       uint64, node count N
+//	edges       uint64, edge count E
+//	urlOffsets  (N+1) x uint64, URL i is urls[urlOffsets[i]:urlOffsets[i+1]]
+//	rowPtr      (N+1) x uint64, node i's backlinks are sources[rowPtr[i]:rowPtr[i+1]]
+//	sources     E x uint32, indices of linking nodes
+//	urls        the URL strings, concatenated in sorted order
+const (
+	mmapMagic      = "GGLGRPH1"
+	mmapHeaderSize = len(mmapMagic) + 16
+)
+
+var errGraphClosed = errors.New("pagerank: graph is closed")
+
+// MemoryMappedGraph is a GraphDB over a file written by
+// WriteMemoryMappedGraph. The file is mapped into memory rather than read,
+// so opening it costs little heap however large the graph; URLs and
+// backlinks are decoded only when asked for.
+type MemoryMappedGraph struct {
+	data   []byte
+	unmap  func() error
+	n, e   int
+	offs   int // start of urlOffsets
+	rows   int // start of rowPtr
+	srcs   int // start of sources
+	blob   int // start of the URL strings
+	closed bool
+}
+
+// WriteMemoryMappedGraph writes the graph in the format
+// OpenMemoryMappedGraph reads. Pages that appear only in outlinksCount are
+// kept as isolated nodes.
+func WriteMemoryMappedGraph(w io.Writer, backlinks map[string][]string, outlinksCount map[string]int) error {
+	urls := collectURLs(backlinks, outlinksCount)
+	index := make(map[string]uint32, len(urls))
+	for i, url := range urls {
+		index[url] = uint32(i)
+	}
+	edges := 0
+	for _, url := range urls {
+		edges += len(backlinks[url])
+	}
+
+	bw := bufio.NewWriter(w)
+	put := func(v uint64) {
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], v)
+		bw.Write(buf[:])
+	}
+	bw.WriteString(mmapMagic)
+	put(uint64(len(urls)))
+	put(uint64(edges))
+	var off uint64
+	put(0)
+	for _, url := range urls {
+		off += uint64(len(url))
+		put(off)
+	}
+	var row uint64
+	put(0)
+	for _, url := range urls {
+		row += uint64(len(backlinks[url]))
+		put(row)
+	}
+	for _, url := range urls {
+		for _, src := range backlinks[url] {
+			var buf [4]byte
+			binary.LittleEndian.PutUint32(buf[:], index[src])
+			bw.Write(buf[:])
+		}
+	}
+	for _, url := range urls {
+		bw.WriteString(url)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("pagerank: write graph: %w", err)
+	}
+	return nil
+}
+
+// OpenMemoryMappedGraph maps path, checking that it is a well-formed
+// graph file. Close the graph to release the mapping.
+func OpenMemoryMappedGraph(path string) (*MemoryMappedGraph, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: map %s: %w", path, err)
+	}
+	g, err := parseMappedGraph(data)
+	if err != nil {
+		unmap()
+		return nil, fmt.Errorf("pagerank: %s: %w", path, err)
+	}
+	g.unmap = unmap
+	return g, nil
+}
+
+func parseMappedGraph(data []byte) (*MemoryMappedGraph, error) {
+	if len(data) < mmapHeaderSize || string(data[:len(mmapMagic)]) != mmapMagic {
+		return nil, errors.New("not a graph file")
+	}
+	n := binary.LittleEndian.Uint64(data[8:])
+	e := binary.LittleEndian.Uint64(data[16:])
+	size := uint64(len(data))
+	if n >= size/16 || e >= size/4 {
+		return nil, errors.New("truncated graph file")
+	}
+	g := &MemoryMappedGraph{data: data, n: int(n), e: int(e)}
+	g.offs = mmapHeaderSize
+	g.rows = g.offs + 8*(g.n+1)
+	g.srcs = g.rows + 8*(g.n+1)
+	g.blob = g.srcs + 4*g.e
+	if g.blob > len(data) {
+		return nil, errors.New("truncated graph file")
+	}
+	// Check every offset now so the accessors cannot go out of range.
+	for i := 0; i < g.n; i++ {
+		if g.offset(g.offs, i) > g.offset(g.offs, i+1) || g.offset(g.rows, i) > g.offset(g.rows, i+1) {
+			return nil, errors.New("corrupt offsets")
+		}
+	}
+	if g.offset(g.offs, 0) != 0 || g.blob+g.offset(g.offs, g.n) != len(data) {
+		return nil, errors.New("corrupt URL table")
+	}
+	if g.offset(g.rows, 0) != 0 || g.offset(g.rows, g.n) != g.e {
+		return nil, errors.New("corrupt backlink table")
+	}
+	for k := 0; k < g.e; k++ {
+		if int(binary.LittleEndian.Uint32(data[g.srcs+4*k:])) >= g.n {
+			return nil, errors.New("backlink source out of range")
+		}
+	}
+	return g, nil
+}
+
+func (g *MemoryMappedGraph) offset(table, i int) int {
+	return int(binary.LittleEndian.Uint64(g.data[table+8*i:]))
+}
+
+func (g *MemoryMappedGraph) url(i int) string {
+	return string(g.data[g.blob+g.offset(g.offs, i) : g.blob+g.offset(g.offs, i+1)])
+}
+
+// Nodes returns every URL, sorted.
+func (g *MemoryMappedGraph) Nodes() ([]string, error) {
+	if g.closed {
+		return nil, errGraphClosed
+	}
+	urls := make([]string, g.n)
+	for i := range urls {
+		urls[i] = g.url(i)
+	}
+	return urls, nil
+}
+
+// Backlinks returns the pages linking to url, or nil if url is not in the
+// graph.
+func (g *MemoryMappedGraph) Backlinks(url string) ([]string, error) {
+	if g.closed {
+		return nil, errGraphClosed
+	}
+	i := sort.Search(g.n, func(i int) bool { return g.url(i) >= url })
+	if i == g.n || g.url(i) != url {
+		return nil, nil
+	}
+	lo, hi := g.offset(g.rows, i), g.offset(g.rows, i+1)
+	if lo == hi {
+		return nil, nil
+	}
+	sources := make([]string, hi-lo)
+	for k := range sources {
+		sources[k] = g.url(int(binary.LittleEndian.Uint32(g.data[g.srcs+4*(lo+k):])))
+	}
+	return sources, nil
+}
+
+// Close releases the mapping. The graph cannot be used afterwards.
+func (g *MemoryMappedGraph) Close() error {
+	if g.closed {
+		return nil
+	}
+	g.closed = true
+	g.data = nil
+	if err := g.unmap(); err != nil {
+		return fmt.Errorf("pagerank: unmap graph: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package pagerank
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeGraphFile(t *testing.T, backlinks map[string][]string, outlinks map[string]int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WriteMemoryMappedGraph(&buf, backlinks, outlinks); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "graph.bin")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMemoryMappedGraphRoundTrip(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(300, 3, 2)
+	outlinks["isolated"] = 0
+	g, err := OpenMemoryMappedGraph(writeGraphFile(t, backlinks, outlinks))
+	if err != nil {
+		t.Fatalf("OpenMemoryMappedGraph: %v", err)
+	}
+	defer g.Close()
+
+	nodes, err := g.Nodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := collectURLs(backlinks, outlinks); !reflect.DeepEqual(nodes, want) {
+		t.Fatalf("nodes differ: got %d, want %d", len(nodes), len(want))
+	}
+	for _, url := range nodes {
+		got, err := g.Backlinks(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := backlinks[url]
+		if len(want) == 0 {
+			want = nil
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Backlinks(%s) = %v, want %v", url, got, want)
+		}
+	}
+	if got, _ := g.Backlinks("missing"); got != nil {
+		t.Errorf("unknown URL has backlinks %v", got)
+	}
+
+	fromDB, err := New().CalculateFromDB(g)
+	if err != nil {
+		t.Fatalf("CalculateFromDB: %v", err)
+	}
+	derived, counts, _ := LoadGraph(mapGraphDB(backlinks))
+	counts["isolated"] = 0
+	if want := New().Calculate(derived, counts); !reflect.DeepEqual(fromDB, want) {
+		t.Error("CalculateFromDB over the mapped graph ranks differently")
+	}
+
+	if err := g.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := g.Nodes(); err == nil {
+		t.Error("Nodes after Close should fail")
+	}
+}
+
+func TestOpenMemoryMappedGraphRejectsCorruptFiles(t *testing.T) {
+	path := writeGraphFile(t, map[string][]string{"b": {"a"}}, nil)
+	data, _ := os.ReadFile(path)
+	dir := t.TempDir()
+	cases := map[string][]byte{
+		"empty":     {},
+		"magic":     append([]byte("NOTAGRPH"), data[8:]...),
+		"truncated": data[:len(data)-1],
+	}
+	for name, content := range cases {
+		p := filepath.Join(dir, name)
+		os.WriteFile(p, content, 0o644)
+		if g, err := OpenMemoryMappedGraph(p); err == nil {
+			g.Close()
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := OpenMemoryMappedGraph(filepath.Join(dir, "missing")); err == nil {
+		t.Error("missing file: expected an error")
+	}
+}
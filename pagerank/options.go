@@ -0,0 +1,36 @@
+package pagerank
+
+// Option configures a Calculator built by NewWithOptions. Options apply
+// in order, so a later option overrides an earlier one, and invalid
+// values are ignored just as the matching setter ignores them.
+type Option func(*Calculator)
+
+// NewWithOptions returns New's defaults with opts applied.
+func NewWithOptions(opts ...Option) *Calculator {
+	c := New()
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithDamping is SetDamping; values outside (0, 1) are ignored.
+func WithDamping(d float64) Option {
+	return func(c *Calculator) { c.SetDamping(d) }
+}
+
+// WithIterations is SetIterations; values below 1 are ignored.
+func WithIterations(n int) Option {
+	return func(c *Calculator) { c.SetIterations(n) }
+}
+
+// WithTolerance is SetTolerance.
+func WithTolerance(eps float64) Option {
+	return func(c *Calculator) { c.SetTolerance(eps) }
+}
+
+// WithPersonalization directs teleportation towards seeds, as
+// SetRestartProbabilities does.
+func WithPersonalization(seeds map[string]float64) Option {
+	return func(c *Calculator) { c.SetRestartProbabilities(seeds) }
+}
@@ -0,0 +1,34 @@
+package pagerank
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	seeds := map[string]float64{"page-a": 1}
+	calc := NewWithOptions(WithDamping(0.9), WithIterations(30), WithTolerance(1e-9), WithPersonalization(seeds))
+	want := New().SetDamping(0.9).SetIterations(30).SetTolerance(1e-9).SetRestartProbabilities(seeds)
+	if calc.String() != want.String() {
+		t.Errorf("options gave %s, want %s", calc, want)
+	}
+	if got := calc.Calculate(backlinks, outlinks); !reflect.DeepEqual(got, want.Calculate(backlinks, outlinks)) {
+		t.Error("options and setters rank differently")
+	}
+
+	if got := NewWithOptions().String(); got != New().String() {
+		t.Errorf("no options gave %s", got)
+	}
+}
+
+func TestNewWithOptionsLastWins(t *testing.T) {
+	calc := NewWithOptions(WithDamping(0.7), WithIterations(10), WithDamping(0.95), WithIterations(20))
+	if calc.Damping() != 0.95 || calc.Iterations() != 20 {
+		t.Errorf("got damping %v, iterations %d; want the last values", calc.Damping(), calc.Iterations())
+	}
+	// An invalid value is ignored, so the earlier one stands.
+	if got := NewWithOptions(WithDamping(0.7), WithDamping(1.5)).Damping(); got != 0.7 {
+		t.Errorf("invalid damping replaced a valid one: %v", got)
+	}
+}
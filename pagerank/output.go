@@ -0,0 +1,107 @@
+package pagerank
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const defaultColWidth = 40
+
+// WriteResults writes the top limit results to w as a fixed-width table.
+func WriteResults(w io.Writer, results []Result, limit int) error {
+	return WriteResultsTable(w, results, limit, defaultColWidth)
+}
+
+// WriteResultsTable is WriteResults with a configurable URL column width.
+func WriteResultsTable(w io.Writer, results []Result, limit int, colWidth int) error {
+	return writeTable(w, results, limit, colWidth, nil)
+}
+
+// WriteResultsColored is WriteResults that, when w is a terminal, shows
+// the top 10% of all results in green, the bottom 10% in red and the rest
+// in white. Anything else gets plain text.
+func WriteResultsColored(w io.Writer, results []Result, limit int) error {
+	if f, ok := w.(*os.File); !ok || !term.IsTerminal(int(f.Fd())) {
+		return WriteResults(w, results, limit)
+	}
+	return writeTable(w, results, limit, defaultColWidth, func(i int) string {
+		return rankBandColor(i, len(results))
+	})
+}
+
+// rankBandColor picks the color for the result at position i of n.
+func rankBandColor(i, n int) string {
+	band := n / 10
+	if band < 1 {
+		band = 1
+	}
+	switch {
+	case i < band:
+		return ansiGreen
+	case i >= n-band:
+		return ansiRed
+	}
+	return ansiWhite
+}
+
+// writeTable writes the table, wrapping row i in color(i) if color is set.
+func writeTable(w io.Writer, results []Result, limit int, colWidth int, color func(i int) string) error {
+	if limit > len(results) {
+		limit = len(results)
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	if colWidth < 1 {
+		colWidth = 1
+	}
+	if _, err := fmt.Fprintf(w, "Top %d PageRank Results:\n", limit); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, strings.Repeat("=", colWidth+10)); err != nil {
+		return err
+	}
+	for i := 0; i < limit; i++ {
+		row := fmt.Sprintf("%-*s | %.8f", colWidth, results[i].URL, results[i].Rank)
+		if color != nil {
+			row = color(i) + row + ansiReset
+		}
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteResultsMarkdown writes the top limit results as a Markdown table
+// with Rank, URL and Score columns. Pipes in URLs are escaped.
+func WriteResultsMarkdown(w io.Writer, results []Result, limit int) error {
+	if limit > len(results) {
+		limit = len(results)
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	if _, err := fmt.Fprint(w, "| Rank | URL | Score |\n| ---: | --- | ---: |\n"); err != nil {
+		return err
+	}
+	for i := 0; i < limit; i++ {
+		url := strings.ReplaceAll(results[i].URL, "|", `\|`)
+		if _, err := fmt.Fprintf(w, "| %d | %s | %.8f |\n", i+1, url, results[i].Rank); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Print writes results to stdout.
+//
+// Deprecated: use WriteResults, which accepts any io.Writer and reports
+// write errors.
+func Print(results []Result, limit int) {
+	_ = WriteResults(os.Stdout, results, limit)
+}
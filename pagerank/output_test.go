@@ -0,0 +1,117 @@
+package pagerank
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteResults(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	results := New().Calculate(backlinks, outlinks)
+
+	var buf bytes.Buffer
+	if err := WriteResults(&buf, results, 2); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	if lines[0] != "Top 2 PageRank Results:" {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if lines[1] != strings.Repeat("=", 50) {
+		t.Errorf("unexpected separator %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], results[0].URL+" ") {
+		t.Errorf("first row should start with top URL, got %q", lines[2])
+	}
+}
+
+func TestWriteResultsLimitClamped(t *testing.T) {
+	results := []Result{{URL: "page-a", Rank: 1}}
+	var buf bytes.Buffer
+	if err := WriteResults(&buf, results, 10); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "Top 1 PageRank Results:") {
+		t.Errorf("limit should clamp to result count, got %q", buf.String())
+	}
+}
+
+func TestWriteResultsTablePadsColumns(t *testing.T) {
+	results := []Result{{URL: "page-a", Rank: 0.5}}
+	var buf bytes.Buffer
+	if err := WriteResultsTable(&buf, results, 1, 12); err != nil {
+		t.Fatalf("WriteResultsTable: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if want := "page-a       | 0.50000000"; lines[2] != want {
+		t.Errorf("row = %q, want %q", lines[2], want)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriteResultsReturnsWriteError(t *testing.T) {
+	results := []Result{{URL: "page-a", Rank: 0.5}}
+	if err := WriteResults(failingWriter{}, results, 1); err == nil {
+		t.Error("expected write error to be returned")
+	}
+}
+
+func TestWriteResultsColoredPlainForNonTerminal(t *testing.T) {
+	results := New().Calculate(syntheticGraph(30, 3, 1))
+	var colored, plain bytes.Buffer
+	if err := WriteResultsColored(&colored, results, 20); err != nil {
+		t.Fatal(err)
+	}
+	WriteResults(&plain, results, 20)
+	if bytes.Contains(colored.Bytes(), []byte("\x1b")) {
+		t.Errorf("non-terminal output contains ANSI escapes: %q", colored.String())
+	}
+	if colored.String() != plain.String() {
+		t.Errorf("non-terminal output differs from WriteResults:\n%s\nvs\n%s", colored.String(), plain.String())
+	}
+}
+
+func TestRankBandColor(t *testing.T) {
+	want := map[int]string{0: ansiGreen, 2: ansiGreen, 3: ansiWhite, 26: ansiWhite, 27: ansiRed, 29: ansiRed}
+	for i, color := range want {
+		if got := rankBandColor(i, 30); got != color {
+			t.Errorf("rankBandColor(%d, 30) = %q, want %q", i, got, color)
+		}
+	}
+}
+
+func TestWriteResultsMarkdown(t *testing.T) {
+	results := []Result{
+		{"https://example.com/a", 0.5},
+		{"https://example.com/search?q=a|b", 0.3},
+		{"https://example.com/c", 0.2},
+	}
+	var buf bytes.Buffer
+	if err := WriteResultsMarkdown(&buf, results, 2); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2+2 {
+		t.Fatalf("expected 4 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	if lines[0] != "| Rank | URL | Score |" {
+		t.Errorf("header = %q", lines[0])
+	}
+	for i, line := range lines {
+		// Escaped pipes are part of the cell, not delimiters.
+		if n := strings.Count(line, "|") - strings.Count(line, `\|`); n != 4 {
+			t.Errorf("line %d has %d delimiters: %q", i, n, line)
+		}
+	}
+	if want := `| 2 | https://example.com/search?q=a\|b | 0.30000000 |`; lines[3] != want {
+		t.Errorf("row = %q, want %q", lines[3], want)
+	}
+}
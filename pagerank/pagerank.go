@@ -1,8 +1,27 @@
 package pagerank
 
 import (
+	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/rand"
 	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrMemoryLimitExceeded is returned when a graph's estimated footprint
+	// is over the SetMaxMemoryBytes limit.
+	ErrMemoryLimitExceeded = errors.New("pagerank: graph exceeds memory limit")
+	// ErrGraphTooLarge is returned when a graph has more pages or links
+	// than SetMaxNodes or SetMaxEdges allow. It is checked before any index
+	// is built.
+	ErrGraphTooLarge = errors.New("pagerank: graph exceeds size limit")
 )
 
 type Result struct {
@@ -12,7 +31,85 @@ type Result struct {
 
 type Calculator struct {
 	damping    float64
+	adaptive   *adaptiveDamping
 	iterations int
+	tolerance  float64
+
+	maxInlinks int
+	rng        *rand.Rand
+
+	maxRankCap float64
+
+	restart  map[string]float64
+	teleport map[string]map[string]float64
+
+	// observe, if set, is called with the rank vector after every
+	// iteration. The slice is reused and must not be retained. It runs
+	// alongside the rank logger, which SetRankLogger sets.
+	observe func(iteration int, urls []string, rank []float64)
+	rankLog *csv.Writer
+	// diagnostic receives every link's contribution in the first
+	// iteration; see SetDiagnosticMode.
+	diagnostic io.Writer
+
+	maxMemory int64
+	maxNodes  int
+	maxEdges  int
+	faults    *FaultInjector
+	precision Precision
+	method    IterationMethod
+
+	// fullyCorrect spreads the rank of pages without outlinks over every
+	// page; see SetFullyCorrectFormulation.
+	fullyCorrect bool
+
+	normalizer OutlinkNormalizer
+	classifier LinkClassifier
+	undirected bool
+	absorbing  map[string]bool
+	spam       map[string]float64
+
+	focus          map[string]bool
+	historyEnabled bool
+	history        map[string][]float64
+}
+
+// Precision selects the element type of the rank vectors during the
+// iteration.
+type Precision int
+
+const (
+	Float64 Precision = iota
+	// Float32 halves the rank vectors' memory at the cost of about seven
+	// significant digits.
+	Float32
+)
+
+func (p Precision) String() string {
+	if p == Float32 {
+		return "float32"
+	}
+	return "float64"
+}
+
+// IterationMethod selects how each iteration updates the rank vector.
+type IterationMethod int
+
+const (
+	// Jacobi computes every new rank from the previous iteration's ranks.
+	Jacobi IterationMethod = iota
+	// GaussSeidel updates the ranks in place, so pages later in an
+	// iteration already see the new ranks of pages earlier in it. It
+	// reaches the same ranks, often in about half the iterations, but
+	// always runs on a single worker.
+	GaussSeidel
+)
+
+func (m IterationMethod) String() string {
+	if m == GaussSeidel {
+		return "gauss-seidel"
+	}
+	return "jacobi"
 }
 
 func New() *Calculator {
@@ -36,82 +133,1068 @@ func (c *Calculator) SetIterations(n int) *Calculator {
 	return c
 }
 
+// SetTolerance stops the iteration early once the ranks change by less
+// than eps in total (L1 distance) from one iteration to the next. The
+// iteration count remains the upper bound. Zero disables the check.
+func (c *Calculator) SetTolerance(eps float64) *Calculator {
+	if eps >= 0 {
+		c.tolerance = eps
+	}
+	return c
+}
+
+// SetMaxInlinksPerNode caps how many backlinks are read per node each
+// iteration. Larger inlink sets are randomly sampled and the sampled sum is
+// scaled up so it stays an unbiased estimate. Zero disables sampling.
+func (c *Calculator) SetMaxInlinksPerNode(max int) *Calculator {
+	if max >= 0 {
+		c.maxInlinks = max
+	}
+	return c
+}
+
+// SetSamplingRNG sets the random source used for inlink sampling.
+func (c *Calculator) SetSamplingRNG(rng *rand.Rand) *Calculator {
+	c.rng = rng
+	return c
+}
+
+// SetMaxRankCap limits every page's rank to cap after each iteration. The
+// excess is shared equally among the pages still below the cap, so total
+// rank is preserved. A cap below 1/N cannot be met and is raised to 1/N.
+// Zero disables the cap.
+func (c *Calculator) SetMaxRankCap(cap float64) *Calculator {
+	if cap >= 0 {
+		c.maxRankCap = cap
+	}
+	return c
+}
+
+// SetRestartProbabilities directs teleportation towards specific pages.
+// restart[url] is the share of the total teleport mass (1-d) that lands on
+// url; pages not listed split whatever share is left over. If the listed
+// shares add up to more than 1 they are scaled down to sum to 1. Values
+// outside [0, 1] are ignored.
+func (c *Calculator) SetRestartProbabilities(restart map[string]float64) *Calculator {
+	c.restart = nil
+	for url, p := range restart {
+		if p < 0 || p > 1 {
+			continue
+		}
+		if c.restart == nil {
+			c.restart = make(map[string]float64, len(restart))
+		}
+		c.restart[url] = p
+	}
+	return c
+}
+
+// SetCustomTeleportMatrix gives each page its own teleport distribution:
+// mat[from][to] is the chance that a jump from "from" lands on "to". Each
+// iteration the teleport term for a page becomes (1-d) * sum_j PR[j] *
+// mat[j][page]. Rows are normalized to sum to 1; pages without a row jump
+// uniformly. When set, the matrix takes precedence over restart
+// probabilities.
+func (c *Calculator) SetCustomTeleportMatrix(mat map[string]map[string]float64) *Calculator {
+	c.teleport = nil
+	for from, row := range mat {
+		var sum float64
+		for _, p := range row {
+			if p > 0 {
+				sum += p
+			}
+		}
+		if sum == 0 {
+			continue
+		}
+		if c.teleport == nil {
+			c.teleport = make(map[string]map[string]float64, len(mat))
+		}
+		norm := make(map[string]float64, len(row))
+		for to, p := range row {
+			if p > 0 {
+				norm[to] = p / sum
+			}
+		}
+		c.teleport[from] = norm
+	}
+	return c
+}
+
+// SetMaxMemoryBytes makes CalculateContext fail with
+// ErrMemoryLimitExceeded when EstimateMemoryBytes puts the graph above
+// limit. Zero disables the check.
+func (c *Calculator) SetMaxMemoryBytes(limit int64) *Calculator {
+	if limit >= 0 {
+		c.maxMemory = limit
+	}
+	return c
+}
+
+// SetMaxNodes makes CalculateContext fail with ErrGraphTooLarge when the
+// graph has more than max pages. Zero disables the check.
+func (c *Calculator) SetMaxNodes(max int) *Calculator {
+	if max >= 0 {
+		c.maxNodes = max
+	}
+	return c
+}
+
+// SetMaxEdges makes CalculateContext fail with ErrGraphTooLarge when the
+// graph has more than max links. Zero disables the check.
+func (c *Calculator) SetMaxEdges(max int) *Calculator {
+	if max >= 0 {
+		c.maxEdges = max
+	}
+	return c
+}
+
+// OutlinkNormalizer decides how a page's rank is split across its links:
+// it returns a weight for each target in outlinks, and the weights should
+// sum to 1.
+type OutlinkNormalizer func(source string, outlinks []string, counts map[string]int) map[string]float64
+
+// SetOutlinkNormalizer replaces the default equal split (1/outlinksCount
+// per link) with fn. fn is called once per linking page with its distinct
+// targets, sorted. Weights are rescaled to sum to 1 and negative or
+// missing weights count as 0; if fn gives a page no positive weight, that
+// page keeps the default split. nil restores the default.
+func (c *Calculator) SetOutlinkNormalizer(fn OutlinkNormalizer) *Calculator {
+	c.normalizer = fn
+	return c
+}
+
+// SetLinkClassifier weights each link by its type: editorial links pass
+// their full share, paid and nofollow links nothing, and unknown links
+// half. nil treats every link as editorial again.
+func (c *Calculator) SetLinkClassifier(lc LinkClassifier) *Calculator {
+	c.classifier = lc
+	return c
+}
+
+// SetUndirected makes every link count in both directions: before ranking,
+// each link gets its reverse added, unless the graph already has it, and
+// the outlink counts are raised to match. The caller's maps are not
+// modified.
+func (c *Calculator) SetUndirected(undirected bool) *Calculator {
+	c.undirected = undirected
+	return c
+}
+
+// SetAbsorbingNodes marks pages that keep the rank they receive: they are
+// ranked as usual, but pass nothing along their outlinks, as if they had
+// none. nil or an empty slice clears the set.
+func (c *Calculator) SetAbsorbingNodes(nodes []string) *Calculator {
+	c.absorbing = nil
+	if len(nodes) > 0 {
+		c.absorbing = make(map[string]bool, len(nodes))
+		for _, url := range nodes {
+			c.absorbing[url] = true
+		}
+	}
+	return c
+}
+
+// SetSpamScores penalizes suspected link farms, usually scored by
+// ComputeSpamScore: the damping applied to the rank a page passes on is
+// multiplied by 1 - scores[url]. Scores outside [0, 1] are ignored, and
+// nil clears the penalty.
+func (c *Calculator) SetSpamScores(scores map[string]float64) *Calculator {
+	c.spam = nil
+	for url, s := range scores {
+		if s < 0 || s > 1 {
+			continue
+		}
+		if c.spam == nil {
+			c.spam = make(map[string]float64)
+		}
+		c.spam[url] = s
+	}
+	return c
+}
+
+// SetRankLogger writes an iteration,url,rank row for every page after
+// each iteration, and flushes w when the calculation ends. Check w.Error
+// for write failures. nil stops logging.
+func (c *Calculator) SetRankLogger(w *csv.Writer) *Calculator {
+	c.rankLog = w
+	return c
+}
+
+func logRanks(w *csv.Writer, iteration int, urls []string, rank []float64) {
+	step := strconv.Itoa(iteration)
+	for i, url := range urls {
+		w.Write([]string{step, url, strconv.FormatFloat(rank[i], 'g', -1, 64)})
+	}
+}
+
+// SetPrecision picks the float type the rank vectors are kept in. Results
+// are converted to float64 either way. Unknown values are ignored.
+func (c *Calculator) SetPrecision(prec Precision) *Calculator {
+	if prec == Float32 || prec == Float64 {
+		c.precision = prec
+	}
+	return c
+}
+
+// SetIterationMethod picks Jacobi or Gauss-Seidel iteration. Unknown
+// values are ignored.
+func (c *Calculator) SetIterationMethod(method IterationMethod) *Calculator {
+	if method == Jacobi || method == GaussSeidel {
+		c.method = method
+	}
+	return c
+}
+
+// SetFullyCorrectFormulation iterates the textbook Google matrix
+// M = d*A + (1-d)*E/N, in which a page with no outlinks links to every
+// page, instead of letting its rank leak away. On a plain graph the ranks
+// then sum to 1 and are the eigenvector of M for eigenvalue 1. Rank that
+// other settings hold back still leaks: absorbing pages spread nothing, a
+// page without links spreads only 1 minus its spam score, and spam scores,
+// link classifiers and outlink counts above the links listed still cut
+// what a linking page passes on. CalculateCSR and CalculateCompact keep
+// the leaky formulation.
+func (c *Calculator) SetFullyCorrectFormulation(enabled bool) *Calculator {
+	c.fullyCorrect = enabled
+	return c
+}
+
+// Clone returns a copy of the calculator's configuration that can run
+// alongside the original. The rank logger and diagnostic writer are not
+// copied, since both calculators would write to them at once; set them
+// again on the clone if wanted. The sampling RNG, fault injector, outlink
+// normalizer and link classifier are shared: give a clone that samples
+// inlinks its own RNG before running both concurrently.
+func (c *Calculator) Clone() *Calculator {
+	clone := *c
+	clone.observe = nil
+	clone.rankLog = nil
+	clone.diagnostic = nil
+	clone.history = nil
+	clone.restart = copyFloatMap(c.restart)
+	clone.spam = copyFloatMap(c.spam)
+	clone.focus = copyBoolMap(c.focus)
+	clone.absorbing = copyBoolMap(c.absorbing)
+	if c.teleport != nil {
+		clone.teleport = make(map[string]map[string]float64, len(c.teleport))
+		for from, row := range c.teleport {
+			clone.teleport[from] = copyFloatMap(row)
+		}
+	}
+	return &clone
+}
+
+func copyFloatMap(m map[string]float64) map[string]float64 {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]float64, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func copyBoolMap(m map[string]bool) map[string]bool {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]bool, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
 func (c *Calculator) Damping() float64 { return c.damping }
 func (c *Calculator) Iterations() int  { return c.iterations }
 
+// Calculate ranks every page in the graph, highest first. It returns an
+// empty result if the graph is rejected by a configured limit or the
+// calculation fails; use CalculateContext to get the error.
 func (c *Calculator) Calculate(backlinks map[string][]string, outlinksCount map[string]int) []Result {
-	allURLs := make(map[string]bool)
-	for url := range backlinks {
-		allURLs[url] = true
+	return c.CalculateParallel(backlinks, outlinksCount, 1)
+}
+
+// CalculateContext is Calculate with error reporting. It stops early with
+// ctx's error if ctx is cancelled between iterations.
+func (c *Calculator) CalculateContext(ctx context.Context, backlinks map[string][]string, outlinksCount map[string]int) ([]Result, error) {
+	return c.calculate(ctx, backlinks, outlinksCount, 1)
+}
+
+// CalculateParallel is Calculate with each iteration's per-page updates
+// split across workers goroutines. Results are identical to Calculate.
+// Inlink sampling shares one RNG, so it always runs on a single worker.
+func (c *Calculator) CalculateParallel(backlinks map[string][]string, outlinksCount map[string]int, workers int) []Result {
+	results, err := c.calculate(context.Background(), backlinks, outlinksCount, workers)
+	if err != nil {
+		return []Result{}
 	}
-	for url := range outlinksCount {
-		allURLs[url] = true
+	return results
+}
+
+func (c *Calculator) calculate(ctx context.Context, backlinks map[string][]string, outlinksCount map[string]int, workers int) ([]Result, error) {
+	g, err := c.index(backlinks, outlinksCount)
+	if err != nil || len(g.urls) == 0 {
+		return []Result{}, err
 	}
-	for _, sources := range backlinks {
-		for _, src := range sources {
-			allURLs[src] = true
+	return c.rankResults(ctx, g, workers)
+}
+
+// index checks the configured limits and builds the link index.
+func (c *Calculator) index(backlinks map[string][]string, outlinksCount map[string]int) (*linkIndex, error) {
+	if c.undirected {
+		backlinks, outlinksCount = symmetrize(backlinks, outlinksCount)
+	}
+	if c.maxEdges > 0 {
+		if edges := EdgeCount(backlinks); edges > c.maxEdges {
+			return nil, fmt.Errorf("%w: %d links, limit %d", ErrGraphTooLarge, edges, c.maxEdges)
+		}
+	}
+	if c.maxNodes > 0 {
+		if urlCountExceeds(backlinks, outlinksCount, c.maxNodes) {
+			return nil, fmt.Errorf("%w: more than %d pages", ErrGraphTooLarge, c.maxNodes)
+		}
+	}
+	if c.maxMemory > 0 {
+		if est := EstimateMemoryBytes(backlinks, outlinksCount); est > c.maxMemory {
+			return nil, fmt.Errorf("%w: estimated %d bytes, limit %d", ErrMemoryLimitExceeded, est, c.maxMemory)
+		}
+	}
+	g := buildLinkIndex(backlinks, outlinksCount)
+	if c.normalizer != nil {
+		c.applyNormalizer(g, backlinks, outlinksCount)
+	}
+	if c.classifier != nil {
+		c.applyLinkClassifier(g)
+	}
+	if c.spam != nil {
+		c.applySpamScores(g)
+	}
+	if c.absorbing != nil {
+		keep := make(map[string]float64, len(c.absorbing))
+		for url := range c.absorbing {
+			keep[url] = 0
+		}
+		scaleOutflow(g, keep)
+	}
+	return g, nil
+}
+
+// applyNormalizer fills g.weights from the calculator's normalizer.
+func (c *Calculator) applyNormalizer(g *linkIndex, backlinks map[string][]string, outlinksCount map[string]int) {
+	shares := make(map[string]map[string]float64)
+	for src, targets := range forwardLinks(backlinks) {
+		targets = dedupeSorted(targets)
+		weights := c.normalizer(src, targets, outlinksCount)
+		var sum float64
+		for _, t := range targets {
+			if w := weights[t]; w > 0 {
+				sum += w
+			}
+		}
+		if sum <= 0 {
+			continue
+		}
+		norm := make(map[string]float64, len(targets))
+		for _, t := range targets {
+			if w := weights[t]; w > 0 {
+				norm[t] = w / sum
+			}
+		}
+		shares[src] = norm
+	}
+
+	g.weights = make([][]float64, len(g.urls))
+	for i, target := range g.urls {
+		if len(g.sources[i]) == 0 {
+			continue
+		}
+		g.weights[i] = make([]float64, len(g.sources[i]))
+		for k, src := range g.sources[i] {
+			if norm, ok := shares[g.urls[src]]; ok {
+				g.weights[i][k] = norm[target]
+			} else {
+				g.weights[i][k] = 1 / g.out[src]
+			}
+		}
+	}
+}
+
+func dedupeSorted(urls []string) []string {
+	sorted := append([]string(nil), urls...)
+	sort.Strings(sorted)
+	out := sorted[:0]
+	for i, u := range sorted {
+		if i == 0 || u != sorted[i-1] {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// CalculateSparse gives the same results as Calculate but skips, on every
+// iteration, the pages that have no inlinks. It pays off on graphs where
+// most pages receive no links.
+func (c *Calculator) CalculateSparse(backlinks map[string][]string, outlinksCount map[string]int) []Result {
+	g, err := c.index(backlinks, outlinksCount)
+	if err != nil || len(g.urls) == 0 {
+		return []Result{}
+	}
+	g.active = make([]int, 0, len(backlinks))
+	for i, sources := range g.sources {
+		if len(sources) > 0 {
+			g.active = append(g.active, i)
+		}
+	}
+	results, err := c.rankResults(context.Background(), g, 1)
+	if err != nil {
+		return []Result{}
+	}
+	return results
+}
+
+// CalculateWarmStart is Calculate starting from initialRanks rather than
+// 1/N for every page, which saves iterations when the graph has changed
+// little since initialRanks were computed. Pages missing from
+// initialRanks start at 1/N; the starting vector is not renormalized.
+func (c *Calculator) CalculateWarmStart(backlinks map[string][]string, outlinksCount map[string]int, initialRanks map[string]float64) []Result {
+	g, err := c.index(backlinks, outlinksCount)
+	if err != nil || len(g.urls) == 0 {
+		return []Result{}
+	}
+	g.start = make([]float64, len(g.urls))
+	for i, url := range g.urls {
+		if r, ok := initialRanks[url]; ok {
+			g.start[i] = r
+		} else {
+			g.start[i] = 1.0 / float64(len(g.urls))
 		}
 	}
+	results, err := c.rankResults(context.Background(), g, 1)
+	if err != nil {
+		return []Result{}
+	}
+	return results
+}
 
-	total := len(allURLs)
-	if total == 0 {
+// CalculateSubset runs the full computation but only returns results for
+// targets, sorted by rank. Targets that are not in the graph are skipped.
+func (c *Calculator) CalculateSubset(backlinks map[string][]string, outlinksCount map[string]int, targets []string) []Result {
+	g, err := c.index(backlinks, outlinksCount)
+	if err != nil || len(g.urls) == 0 {
+		return []Result{}
+	}
+	rank, err := c.run(context.Background(), g, 1)
+	if err != nil {
 		return []Result{}
 	}
 
-	rank := make(map[string]float64, total)
-	for url := range allURLs {
-		rank[url] = 1.0 / float64(total)
+	out := make([]Result, 0, len(targets))
+	seen := make(map[string]bool, len(targets))
+	for _, url := range targets {
+		i, ok := g.index[url]
+		if !ok || seen[url] {
+			continue
+		}
+		seen[url] = true
+		out = append(out, Result{URL: url, Rank: rank[i]})
 	}
+	sort.Sort(ByRankDesc(out))
+	return out
+}
+
+// linkIndex is the graph with URLs replaced by their position in the
+// sorted URL list, so the iteration works on plain slices.
+type linkIndex struct {
+	urls    []string
+	index   map[string]int
+	sources [][]int
+	out     []float64
+
+	// active, when set, lists the pages that have inlinks; the rest only
+	// ever receive their teleport share. See CalculateSparse.
+	active []int
+
+	// weights, when set, holds the share of its source's rank that each
+	// link in sources carries, replacing 1/out. See SetOutlinkNormalizer.
+	weights [][]float64
 
-	teleport := (1.0 - c.damping) / float64(total)
+	// start, when set, is the rank vector to iterate from instead of the
+	// uniform one. See CalculateWarmStart.
+	start []float64
+}
+
+func buildLinkIndex(backlinks map[string][]string, outlinksCount map[string]int) *linkIndex {
+	urls := collectURLs(backlinks, outlinksCount)
+	g := &linkIndex{
+		urls:    urls,
+		index:   make(map[string]int, len(urls)),
+		sources: make([][]int, len(urls)),
+		out:     make([]float64, len(urls)),
+	}
+	for i, url := range urls {
+		g.index[url] = i
+	}
+	for i, url := range urls {
+		out, hasOut := outlinksCount[url]
+		if !hasOut || out == 0 {
+			out = 1
+		}
+		g.out[i] = float64(out)
+		if sources, ok := backlinks[url]; ok {
+			g.sources[i] = make([]int, len(sources))
+			for k, src := range sources {
+				g.sources[i][k] = g.index[src]
+			}
+		}
+	}
+	return g
+}
+
+// resultsOf builds the sorted results, keeping only the pages in focus
+// unless it is nil.
+func resultsOf[F rankFloat](g *linkIndex, rank []F, focus map[string]bool) []Result {
+	if focus != nil {
+		out := make([]Result, 0, len(focus))
+		for i, url := range g.urls {
+			if focus[url] {
+				out = append(out, Result{URL: url, Rank: float64(rank[i])})
+			}
+		}
+		sort.Sort(ByRankDesc(out))
+		return out
+	}
+	out := make([]Result, len(g.urls))
+	for i, url := range g.urls {
+		out[i] = Result{URL: url, Rank: float64(rank[i])}
+	}
+	sort.Sort(ByRankDesc(out))
+	return out
+}
+
+// rankResults runs the power iteration at the configured precision and
+// returns the sorted results.
+func (c *Calculator) rankResults(ctx context.Context, g *linkIndex, workers int) ([]Result, error) {
+	if c.precision == Float32 {
+		rank, err := iterate[float32](ctx, c, g, workers)
+		if err != nil {
+			return nil, err
+		}
+		return resultsOf(g, rank, c.focus), nil
+	}
+	rank, err := iterate[float64](ctx, c, g, workers)
+	if err != nil {
+		return nil, err
+	}
+	return resultsOf(g, rank, c.focus), nil
+}
+
+// run performs the power iteration and returns the final rank vector,
+// indexed like g.urls.
+func (c *Calculator) run(ctx context.Context, g *linkIndex, workers int) ([]float64, error) {
+	if c.precision == Float32 {
+		rank, err := iterate[float32](ctx, c, g, workers)
+		if err != nil {
+			return nil, err
+		}
+		return widen(rank, nil), nil
+	}
+	return iterate[float64](ctx, c, g, workers)
+}
+
+// rankFloat is the element type of the rank vectors; see SetPrecision.
+type rankFloat interface{ ~float32 | ~float64 }
+
+func iterate[F rankFloat](ctx context.Context, c *Calculator, g *linkIndex, workers int) ([]F, error) {
+	if c.rankLog != nil {
+		defer c.rankLog.Flush()
+	}
+	total := len(g.urls)
+	rank := make([]F, total)
+	for i := range rank {
+		rank[i] = F(1.0 / float64(total))
+		if g.start != nil {
+			rank[i] = F(g.start[i])
+		}
+	}
+	next := make([]F, total)
+	teleport := make([]F, total)
+	fillTeleport(c, teleport, g.urls)
+	matrix := c.matrixRows(g)
+
+	var s *sampler
+	if c.maxInlinks > 0 {
+		rng := c.rng
+		if rng == nil {
+			rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		s = &sampler{rng: rng, max: c.maxInlinks}
+		workers = 1
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > total {
+		workers = total
+	}
+	chunk := (total + workers - 1) / workers
+	var observed []float64
+	rec := c.newHistoryRecorder(g)
+
+	var dangling []int
+	var keep []float64
+	var spill []F
+	if c.fullyCorrect {
+		dangling, keep = c.danglingPages(g)
+		spill = make([]F, total)
+	}
+
+	// When no rank leaks away a Gauss-Seidel sweep is renormalized to the
+	// fixed point's total of 1; otherwise its error in the total decays
+	// only at the damping factor. Leaky graphs have no such fixed total.
+	var mass float64
+	if c.method == GaussSeidel && conservesRank(g, dangling, keep) {
+		mass = 1
+	}
+
+	damping := c.damping
+	var base []F
+	var spread float64
+	if c.adaptive != nil {
+		damping = math.Min(math.Max(damping, c.adaptive.min), c.adaptive.max)
+		base = append([]F(nil), teleport...)
+		spread = variance(rank)
+	}
 
 	for i := 0; i < c.iterations; i++ {
-		next := make(map[string]float64, total)
-		for url := range allURLs {
-			next[url] = teleport
-			sources, ok := backlinks[url]
-			if !ok {
-				continue
-			}
-			var contrib float64
-			for _, src := range sources {
-				out, hasOut := outlinksCount[src]
-				if !hasOut || out == 0 {
-					out = 1
-				}
-				if r, exists := rank[src]; exists {
-					contrib += r / float64(out)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if i == 0 && c.diagnostic != nil {
+			if err := writeContributions(c.diagnostic, g, rank, damping); err != nil {
+				return nil, err
+			}
+		}
+		if matrix != nil {
+			teleport = matrixTeleport(matrix, rank, 1.0-damping)
+		} else if base != nil {
+			scale := F((1 - damping) / (1 - c.damping))
+			for k := range teleport {
+				teleport[k] = base[k] * scale
+			}
+		}
+		step := teleport
+		if dangling != nil {
+			// Pages without outlinks pass their rank to every page alike.
+			var sunk F
+			for j, k := range dangling {
+				sunk += F(keep[j]) * rank[k]
+			}
+			share := F(damping) * sunk / F(total)
+			for k := range spill {
+				spill[k] = teleport[k] + share
+			}
+			step = spill
+		}
+		if c.method == GaussSeidel {
+			copy(next, rank)
+			updateGaussSeidel(F(damping), g, next, step, mass, s)
+		} else if g.active != nil && s == nil {
+			updateSparse(F(damping), g, rank, next, step)
+		} else if workers == 1 {
+			update(F(damping), g, rank, next, step, 0, total, s)
+		} else {
+			var wg sync.WaitGroup
+			for lo := 0; lo < total; lo += chunk {
+				hi := lo + chunk
+				if hi > total {
+					hi = total
 				}
+				wg.Add(1)
+				go func(lo, hi int) {
+					defer wg.Done()
+					update(F(damping), g, rank, next, step, lo, hi, nil)
+				}(lo, hi)
+			}
+			wg.Wait()
+		}
+		if c.maxRankCap > 0 {
+			applyRankCap(next, F(math.Max(c.maxRankCap, 1.0/float64(total))))
+		}
+		converged := c.tolerance > 0 && l1Distance(rank, next) < c.tolerance
+		rank, next = next, rank
+		if c.adaptive != nil {
+			prev := spread
+			spread = variance(rank)
+			damping = c.adaptive.next(c.damping, prev, spread, i+1)
+		}
+		if c.observe != nil || c.rankLog != nil {
+			observed = widen(rank, observed)
+		}
+		if c.observe != nil {
+			c.observe(i+1, g.urls, observed)
+		}
+		if c.rankLog != nil {
+			logRanks(c.rankLog, i+1, g.urls, observed)
+		}
+		if rec != nil {
+			recordHistory(rec, rank)
+		}
+		if c.faults != nil {
+			if err := c.faults.check(i + 1); err != nil {
+				return nil, err
 			}
-			next[url] += c.damping * contrib
 		}
-		rank = next
+		if converged {
+			break
+		}
+	}
+	if rec != nil {
+		c.history = rec.byURL(g.urls)
 	}
+	return rank, nil
+}
 
-	return sortResults(rank)
+func l1Distance[F rankFloat](a, b []F) float64 {
+	var sum float64
+	for i := range a {
+		sum += math.Abs(float64(a[i] - b[i]))
+	}
+	return sum
 }
 
-func sortResults(rank map[string]float64) []Result {
-	out := make([]Result, 0, len(rank))
-	for url, r := range rank {
-		out = append(out, Result{URL: url, Rank: r})
+// widen returns rank as float64, converting into buf unless it already
+// is float64.
+func widen[F rankFloat](rank []F, buf []float64) []float64 {
+	if r, ok := any(rank).([]float64); ok {
+		return r
+	}
+	if cap(buf) < len(rank) {
+		buf = make([]float64, len(rank))
 	}
-	sort.Slice(out, func(i, j int) bool {
-		if out[i].Rank == out[j].Rank {
-			return out[i].URL < out[j].URL
+	buf = buf[:len(rank)]
+	for i, r := range rank {
+		buf[i] = float64(r)
+	}
+	return buf
+}
+
+// update computes next[lo:hi] from the previous iteration's rank vector.
+func update[F rankFloat](damping F, g *linkIndex, rank, next, teleport []F, lo, hi int, s *sampler) {
+	if s == nil && g.weights == nil {
+		// The common case, kept free of the call to inflow.
+		for i := lo; i < hi; i++ {
+			var contrib F
+			for _, src := range g.sources[i] {
+				contrib += rank[src] / F(g.out[src])
+			}
+			next[i] = teleport[i] + damping*contrib
 		}
-		return out[i].Rank > out[j].Rank
-	})
-	return out
+		return
+	}
+	for i := lo; i < hi; i++ {
+		var picks []int
+		scale := 1.0
+		if s != nil {
+			picks, scale = s.sample(len(g.sources[i]))
+		}
+		next[i] = teleport[i] + damping*inflow(g, rank, i, picks)*F(scale)
+	}
+}
+
+// inflow sums the rank page i receives over its inlinks, or over just the
+// inlinks at positions picks if that is non-nil.
+func inflow[F rankFloat](g *linkIndex, rank []F, i int, picks []int) F {
+	sources := g.sources[i]
+	var sum F
+	if g.weights == nil {
+		if picks == nil {
+			for _, src := range sources {
+				sum += rank[src] / F(g.out[src])
+			}
+			return sum
+		}
+		for _, k := range picks {
+			sum += rank[sources[k]] / F(g.out[sources[k]])
+		}
+		return sum
+	}
+	weights := g.weights[i]
+	if picks == nil {
+		for k, src := range sources {
+			sum += rank[src] * F(weights[k])
+		}
+		return sum
+	}
+	for _, k := range picks {
+		sum += rank[sources[k]] * F(weights[k])
+	}
+	return sum
+}
+
+// updateSparse is update for the whole vector, touching only the pages
+// in g.active after starting every page from its teleport share.
+func updateSparse[F rankFloat](damping F, g *linkIndex, rank, next, teleport []F) {
+	copy(next, teleport)
+	for _, i := range g.active {
+		if g.weights != nil {
+			next[i] += damping * inflow(g, rank, i, nil)
+			continue
+		}
+		var contrib F
+		for _, src := range g.sources[i] {
+			contrib += rank[src] / F(g.out[src])
+		}
+		next[i] += damping * contrib
+	}
 }
 
-func Print(results []Result, limit int) {
-	if limit > len(results) {
-		limit = len(results)
+// updateGaussSeidel is update over the whole vector in place: each page's
+// new rank is computed from rank as it stands, earlier pages already
+// updated. If total is positive the sweep is then scaled to sum to it.
+func updateGaussSeidel[F rankFloat](damping F, g *linkIndex, rank, teleport []F, total float64, s *sampler) {
+	var sum float64
+	for i := range rank {
+		var picks []int
+		scale := 1.0
+		if s != nil {
+			picks, scale = s.sample(len(g.sources[i]))
+		}
+		rank[i] = teleport[i] + damping*inflow(g, rank, i, picks)*F(scale)
+		sum += float64(rank[i])
 	}
-	fmt.Printf("Top %d PageRank Results:\n", limit)
-	fmt.Println("==================================================")
-	for i := 0; i < limit; i++ {
-		fmt.Printf("%-40s | %.8f\n", results[i].URL, results[i].Rank)
+	if total > 0 && sum > 0 {
+		for i := range rank {
+			rank[i] *= F(total / sum)
+		}
+	}
+}
+
+// conservesRank reports whether every page passes all of its rank on, so
+// that the ranks keep summing to 1. The dangling pages, as returned by
+// danglingPages, count as passing keep of theirs to every page.
+func conservesRank(g *linkIndex, dangling []int, keep []float64) bool {
+	flow := outflowShares(g)
+	for j, k := range dangling {
+		flow[k] += keep[j]
+	}
+	for _, f := range flow {
+		if math.Abs(f-1) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+// danglingPages returns the pages without links of their own, together
+// with the share of its rank each passes to every page: 1 less its spam
+// score. Absorbing pages and pages with a spam score of 1 pass on nothing
+// and are left out. It returns nil if there are none.
+func (c *Calculator) danglingPages(g *linkIndex) (pages []int, keep []float64) {
+	linked := make([]bool, len(g.urls))
+	for _, sources := range g.sources {
+		for _, src := range sources {
+			linked[src] = true
+		}
+	}
+	for i, url := range g.urls {
+		if linked[i] || c.absorbing[url] {
+			continue
+		}
+		if k := 1 - c.spam[url]; k > 0 {
+			pages = append(pages, i)
+			keep = append(keep, k)
+		}
+	}
+	return pages, keep
+}
+
+// outflowShares returns the fraction of each page's rank that its links
+// pass on.
+func outflowShares(g *linkIndex) []float64 {
+	flow := make([]float64, len(g.urls))
+	for i, sources := range g.sources {
+		for k, src := range sources {
+			if g.weights != nil {
+				flow[src] += g.weights[i][k]
+			} else {
+				flow[src] += 1 / float64(g.out[src])
+			}
+		}
+	}
+	return flow
+}
+
+// sampler draws the positions of a random subset of at most max inlinks
+// per page and returns the factor that scales the subset's sum back up. A
+// nil subset means every inlink.
+type sampler struct {
+	rng     *rand.Rand
+	max     int
+	scratch []int
+}
+
+func (s *sampler) sample(n int) ([]int, float64) {
+	if n <= s.max {
+		return nil, 1
+	}
+	s.scratch = s.scratch[:0]
+	for k := 0; k < n; k++ {
+		s.scratch = append(s.scratch, k)
+	}
+	for k := 0; k < s.max; k++ {
+		j := k + s.rng.Intn(len(s.scratch)-k)
+		s.scratch[k], s.scratch[j] = s.scratch[j], s.scratch[k]
+	}
+	return s.scratch[:s.max], float64(n) / float64(s.max)
+}
+
+// teleportVector returns the teleportation term for each of urls.
+func (c *Calculator) teleportVector(urls []string) []float64 {
+	tele := make([]float64, len(urls))
+	fillTeleport(c, tele, urls)
+	return tele
+}
+
+func fillTeleport[F rankFloat](c *Calculator, tele []F, urls []string) {
+	mass := 1.0 - c.damping
+	if len(c.restart) == 0 {
+		for i := range tele {
+			tele[i] = F(mass / float64(len(urls)))
+		}
+		return
+	}
+
+	var listed float64
+	unlisted := 0
+	for _, url := range urls {
+		if p, ok := c.restart[url]; ok {
+			listed += p
+		} else {
+			unlisted++
+		}
+	}
+	scale, rest := 1.0, 0.0
+	if listed > 1 {
+		scale = 1 / listed
+	} else if unlisted > 0 {
+		rest = (1 - listed) / float64(unlisted)
+	} else if listed > 0 {
+		scale = 1 / listed
+	}
+	for i, url := range urls {
+		if p, ok := c.restart[url]; ok {
+			tele[i] = F(mass * p * scale)
+		} else {
+			tele[i] = F(mass * rest)
+		}
+	}
+}
+
+type teleportEntry struct {
+	to int
+	p  float64
+}
+
+// matrixRows resolves the custom teleport matrix against g. A nil row
+// means the page jumps uniformly. Returns nil if no matrix is set.
+func (c *Calculator) matrixRows(g *linkIndex) [][]teleportEntry {
+	if c.teleport == nil {
+		return nil
+	}
+	rows := make([][]teleportEntry, len(g.urls))
+	for i, from := range g.urls {
+		row, ok := c.teleport[from]
+		if !ok {
+			continue
+		}
+		entries := make([]teleportEntry, 0, len(row))
+		for to, p := range row {
+			if j, ok := g.index[to]; ok {
+				entries = append(entries, teleportEntry{to: j, p: p})
+			}
+		}
+		sort.Slice(entries, func(a, b int) bool { return entries[a].to < entries[b].to })
+		rows[i] = entries
+	}
+	return rows
+}
+
+// matrixTeleport evaluates the teleport matrix against the current rank
+// vector.
+func matrixTeleport[F rankFloat](rows [][]teleportEntry, rank []F, mass float64) []F {
+	tele := make([]F, len(rank))
+	var uniform F
+	for from, row := range rows {
+		if row == nil {
+			uniform += rank[from]
+			continue
+		}
+		for _, e := range row {
+			tele[e.to] += F(mass) * rank[from] * F(e.p)
+		}
+	}
+	for i := range tele {
+		tele[i] += F(mass) * uniform / F(len(rank))
+	}
+	return tele
+}
+
+func applyRankCap[F rankFloat](rank []F, cap F) {
+	for {
+		var excess F
+		var under []int
+		for i, r := range rank {
+			if r > cap {
+				excess += r - cap
+				rank[i] = cap
+			} else if r < cap {
+				under = append(under, i)
+			}
+		}
+		if excess <= 0 || len(under) == 0 {
+			return
+		}
+		share := excess / F(len(under))
+		for _, i := range under {
+			rank[i] += share
+		}
+	}
+}
+
+// Rough per-item costs used by EstimateMemoryBytes: a string header, a map
+// entry, the per-page rank vectors and index slices, and per-link slots in
+// the backlink slices and the link index.
+const (
+	stringHeaderBytes = 16
+	mapEntryBytes     = 48
+	perNodeBytes      = 2*mapEntryBytes + 5*8 + 24
+	perEdgeBytes      = stringHeaderBytes + 8
+)
+
+// EstimateMemoryBytes approximates the memory a calculation over the graph
+// needs: the bytes of every URL string it mentions plus fixed overheads per
+// page and per link.
+func EstimateMemoryBytes(backlinks map[string][]string, outlinksCount map[string]int) int64 {
+	var total int64
+	nodes := make(map[string]bool, len(outlinksCount))
+	addNode := func(url string) {
+		if !nodes[url] {
+			nodes[url] = true
+			total += int64(len(url)) + stringHeaderBytes + perNodeBytes
+		}
+	}
+	for url, sources := range backlinks {
+		addNode(url)
+		for _, src := range sources {
+			addNode(src)
+			total += int64(len(src)) + perEdgeBytes
+		}
+	}
+	for url := range outlinksCount {
+		addNode(url)
 	}
+	return total
 }
@@ -0,0 +1,130 @@
+package pagerank
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+)
+
+const (
+	benchDegree     = 4
+	benchIterations = 10
+)
+
+func benchmarkCalculate(b *testing.B, nodes int) {
+	backlinks, outlinks := GeneratePowerLawGraph(nodes, benchDegree, 1)
+	edges := int64(EdgeCount(backlinks))
+	calc := New().SetIterations(benchIterations)
+
+	b.Run("Map", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(edges)
+		for i := 0; i < b.N; i++ {
+			calc.Calculate(backlinks, outlinks)
+		}
+	})
+	b.Run("CSR", func(b *testing.B) {
+		g := BuildCSR(backlinks)
+		b.ReportAllocs()
+		b.SetBytes(edges)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			calc.CalculateCSR(g)
+		}
+	})
+	b.Run("Compact", func(b *testing.B) {
+		g := Compact(backlinks, outlinks)
+		b.ReportAllocs()
+		b.SetBytes(edges)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			calc.CalculateCompact(g)
+		}
+	})
+}
+
+func BenchmarkCalculate_10(b *testing.B)   { benchmarkCalculate(b, 10) }
+func BenchmarkCalculate_1K(b *testing.B)   { benchmarkCalculate(b, 1_000) }
+func BenchmarkCalculate_100K(b *testing.B) { benchmarkCalculate(b, 100_000) }
+func BenchmarkCalculate_1M(b *testing.B)   { benchmarkCalculate(b, 1_000_000) }
+
+// The 10M-node graph needs several GB of heap for the map-based variant,
+// so it only runs when PAGERANK_BENCH_10M is set.
+func BenchmarkCalculate_10M(b *testing.B) {
+	if os.Getenv("PAGERANK_BENCH_10M") == "" {
+		b.Skip("set PAGERANK_BENCH_10M=1 to run the 10M-node benchmark")
+	}
+	benchmarkCalculate(b, 10_000_000)
+}
+
+// sparseGraph returns n pages of which only one in ten links anywhere, each
+// to a single random page: density 0.1/n.
+func sparseGraph(n int) (map[string][]string, map[string]int) {
+	backlinks, outlinks := syntheticGraph(n/10, 1, 1)
+	for i := n / 10; i < n; i++ {
+		outlinks[fmt.Sprintf("page-%d", i)] = 0
+	}
+	return backlinks, outlinks
+}
+
+func BenchmarkCalculateSparse(b *testing.B) {
+	backlinks, outlinks := sparseGraph(200_000)
+	calc := New().SetIterations(benchIterations * 5)
+	b.Run("Dense", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			calc.Calculate(backlinks, outlinks)
+		}
+	})
+	b.Run("Sparse", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			calc.CalculateSparse(backlinks, outlinks)
+		}
+	})
+}
+
+// BenchmarkPrecision times just the power iteration, so B/op is the rank
+// vectors' memory.
+func BenchmarkPrecision(b *testing.B) {
+	backlinks, outlinks := GeneratePowerLawGraph(100_000, benchDegree, 1)
+	g := buildLinkIndex(backlinks, outlinks)
+	calc := New().SetIterations(benchIterations)
+	b.Run("Float64", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			iterate[float64](context.Background(), calc, g, 1)
+		}
+	})
+	b.Run("Float32", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			iterate[float32](context.Background(), calc, g, 1)
+		}
+	})
+}
+
+// BenchmarkWarmStart reranks a graph after one new link, to the same
+// tolerance, from scratch and from the previous ranks. Building the index
+// costs the same either way, so iterations/op shows the saving best.
+func BenchmarkWarmStart(b *testing.B) {
+	backlinks, outlinks := GeneratePowerLawGraph(50_000, benchDegree, 1)
+	calc := New().SetIterations(500).SetTolerance(1e-12)
+	previous := ScoreMap(calc.Calculate(backlinks, outlinks))
+	backlinks["page-1"] = append(backlinks["page-1"], "page-2")
+	outlinks["page-2"]++
+
+	var ran int
+	calc.observe = func(iteration int, _ []string, _ []float64) { ran = iteration }
+	b.Run("Cold", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			calc.Calculate(backlinks, outlinks)
+		}
+		b.ReportMetric(float64(ran), "iterations/op")
+	})
+	b.Run("Warm", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			calc.CalculateWarmStart(backlinks, outlinks, previous)
+		}
+		b.ReportMetric(float64(ran), "iterations/op")
+	})
+}
@@ -1,7 +1,17 @@
 package pagerank
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"math"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
 	"testing"
 )
 
@@ -119,3 +129,757 @@ func TestConvergenceStabilizes(t *testing.T) {
 		}
 	}
 }
+
+func TestCloneIsIndependent(t *testing.T) {
+	orig := New().SetDamping(0.85).SetIterations(40)
+	clone := orig.Clone().SetDamping(0.5)
+
+	if orig.Damping() != 0.85 {
+		t.Errorf("modifying clone changed original damping to %v", orig.Damping())
+	}
+	if clone.Iterations() != 40 {
+		t.Errorf("clone should keep original iterations, got %d", clone.Iterations())
+	}
+
+	backlinks, outlinks := sampleGraph()
+	wantOrig := orig.Calculate(backlinks, outlinks)
+	wantClone := clone.Calculate(backlinks, outlinks)
+
+	var gotOrig, gotClone []Result
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); gotOrig = orig.Calculate(backlinks, outlinks) }()
+	go func() { defer wg.Done(); gotClone = clone.Calculate(backlinks, outlinks) }()
+	wg.Wait()
+
+	for i := range wantOrig {
+		if gotOrig[i] != wantOrig[i] {
+			t.Errorf("original result %d = %v, want %v", i, gotOrig[i], wantOrig[i])
+		}
+		if gotClone[i] != wantClone[i] {
+			t.Errorf("clone result %d = %v, want %v", i, gotClone[i], wantClone[i])
+		}
+	}
+}
+
+// Run with -race: clones of a logging calculator must not share its writer.
+func TestClonesRunConcurrentlyWithRankLogger(t *testing.T) {
+	backlinks, outlinks := syntheticGraph(200, 4, 3)
+	var log bytes.Buffer
+	orig := New().SetIterations(20).
+		SetRestartProbabilities(map[string]float64{"page-1": 0.5}).
+		SetRankLogger(csv.NewWriter(&log))
+	want := orig.Clone().Calculate(backlinks, outlinks)
+
+	clones := make([]*Calculator, 4)
+	got := make([][]Result, len(clones))
+	var wg sync.WaitGroup
+	for i := range clones {
+		clones[i] = orig.Clone()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = clones[i].Calculate(backlinks, outlinks)
+		}(i)
+	}
+	wg.Wait()
+	for i := range got {
+		if !reflect.DeepEqual(got[i], want) {
+			t.Errorf("clone %d gave different results", i)
+		}
+	}
+	if log.Len() != 0 {
+		t.Errorf("clones wrote %d bytes to the original's rank logger", log.Len())
+	}
+
+	clones[0].restart["page-1"] = 0.9
+	if orig.restart["page-1"] != 0.5 {
+		t.Errorf("changing a clone's restart map changed the original's to %v", orig.restart)
+	}
+}
+
+func TestInlinkSamplingNoopWhenUnderLimit(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	want := New().Calculate(backlinks, outlinks)
+	got := New().SetMaxInlinksPerNode(3).
+		SetSamplingRNG(rand.New(rand.NewSource(7))).
+		Calculate(backlinks, outlinks)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("result %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestInlinkSamplingApproximatesFullSum(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	want := map[string]float64{}
+	for _, r := range New().Calculate(backlinks, outlinks) {
+		want[r.URL] = r.Rank
+	}
+
+	const runs = 200
+	avg := map[string]float64{}
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < runs; i++ {
+		calc := New().SetIterations(200).SetMaxInlinksPerNode(1).SetSamplingRNG(rng)
+		for _, r := range calc.Calculate(backlinks, outlinks) {
+			avg[r.URL] += r.Rank / runs
+		}
+	}
+	for url, w := range want {
+		if math.Abs(avg[url]-w)/w > 0.2 {
+			t.Errorf("sampled rank for %s = %.4f, want within 20%% of %.4f", url, avg[url], w)
+		}
+	}
+}
+
+func linkFarmGraph(farms int) (map[string][]string, map[string]int) {
+	backlinks := map[string][]string{}
+	outlinks := map[string]int{"target": 1}
+	for i := 0; i < farms; i++ {
+		src := fmt.Sprintf("farm-%d", i)
+		backlinks["target"] = append(backlinks["target"], src)
+		outlinks[src] = 1
+	}
+	backlinks["farm-0"] = []string{"target"}
+	return backlinks, outlinks
+}
+
+func TestMaxRankCap(t *testing.T) {
+	const cap = 0.26
+	graphs := map[string]func() (map[string][]string, map[string]int){
+		"sample":    sampleGraph,
+		"link-farm": func() (map[string][]string, map[string]int) { return linkFarmGraph(6) },
+	}
+	for name, build := range graphs {
+		backlinks, outlinks := build()
+		uncapped := New().Calculate(backlinks, outlinks)
+		if uncapped[0].Rank <= cap {
+			t.Fatalf("%s: test graph should exceed the cap without it, top rank %.4f", name, uncapped[0].Rank)
+		}
+
+		var sum float64
+		for _, r := range New().SetMaxRankCap(cap).Calculate(backlinks, outlinks) {
+			if r.Rank > cap+1e-12 {
+				t.Errorf("%s: %s rank %.6f exceeds cap %.2f", name, r.URL, r.Rank, cap)
+			}
+			sum += r.Rank
+		}
+		if math.Abs(sum-1.0) > 0.01 {
+			t.Errorf("%s: capped ranks should sum to ~1.0, got %.6f", name, sum)
+		}
+	}
+}
+
+func TestRestartProbabilities(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	const d = 0.85
+	calc := New().SetDamping(d).SetIterations(200).
+		SetRestartProbabilities(map[string]float64{"page-b": 1.0})
+	results := calc.Calculate(backlinks, outlinks)
+
+	rank := map[string]float64{}
+	for _, r := range results {
+		rank[r.URL] = r.Rank
+	}
+	linkShare := func(url string) float64 {
+		var sum float64
+		for _, src := range backlinks[url] {
+			sum += rank[src] / float64(outlinks[src])
+		}
+		return d * sum
+	}
+
+	if got, want := rank["page-b"], (1-d)+linkShare("page-b"); math.Abs(got-want) > 1e-9 {
+		t.Errorf("page-b rank = %.9f, want teleport 1-d plus links = %.9f", got, want)
+	}
+	for _, url := range []string{"page-a", "page-c", "page-d"} {
+		if got, want := rank[url], linkShare(url); math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s should receive no teleport mass: rank %.9f, links %.9f", url, got, want)
+		}
+	}
+}
+
+func TestRestartProbabilitiesPreserveTeleportMass(t *testing.T) {
+	urls := []string{"page-a", "page-b", "page-c", "page-d"}
+	cases := []map[string]float64{
+		nil,
+		{"page-a": 0.5},
+		{"page-a": 0.9, "page-b": 0.9},
+		{"page-a": 0.2, "page-b": 0.2, "page-c": 0.2, "page-d": 0.2},
+	}
+	for _, restart := range cases {
+		tele := New().SetRestartProbabilities(restart).teleportVector(urls)
+		var sum float64
+		for _, v := range tele {
+			sum += v
+		}
+		if math.Abs(sum-0.15) > 1e-12 {
+			t.Errorf("restart %v: teleport mass = %.6f, want 0.15", restart, sum)
+		}
+	}
+}
+
+func TestCustomTeleportMatrixDiagonal(t *testing.T) {
+	backlinks := map[string][]string{
+		"page-a": {"page-d"},
+		"page-b": {"page-a"},
+		"page-c": {"page-b"},
+		"page-d": {"page-c"},
+	}
+	outlinks := map[string]int{"page-a": 1, "page-b": 1, "page-c": 1, "page-d": 1}
+	mat := map[string]map[string]float64{}
+	for url := range outlinks {
+		mat[url] = map[string]float64{url: 1}
+	}
+
+	for _, r := range New().SetCustomTeleportMatrix(mat).Calculate(backlinks, outlinks) {
+		if math.Abs(r.Rank-0.25) > 1e-12 {
+			t.Errorf("diagonal teleport matrix should keep ranks uniform, %s = %v", r.URL, r.Rank)
+		}
+	}
+}
+
+func TestCustomTeleportMatrixMatchesRestart(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	mat := map[string]map[string]float64{}
+	for url := range outlinks {
+		mat[url] = map[string]float64{"page-b": 5}
+	}
+	viaMatrix := New().SetIterations(200).SetCustomTeleportMatrix(mat).Calculate(backlinks, outlinks)
+	viaRestart := New().SetIterations(200).
+		SetRestartProbabilities(map[string]float64{"page-b": 1}).
+		Calculate(backlinks, outlinks)
+	for i := range viaRestart {
+		if viaMatrix[i].URL != viaRestart[i].URL || math.Abs(viaMatrix[i].Rank-viaRestart[i].Rank) > 1e-6 {
+			t.Errorf("result %d: matrix %v, restart %v", i, viaMatrix[i], viaRestart[i])
+		}
+	}
+}
+
+func TestCalculateParallelMatchesCalculate(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(2000, 4, 3)
+	calcs := map[string]*Calculator{
+		"default": New(),
+		"capped":  New().SetMaxRankCap(0.01),
+		"restart": New().SetRestartProbabilities(map[string]float64{"page-7": 0.5}),
+	}
+	for name, calc := range calcs {
+		want := calc.Calculate(backlinks, outlinks)
+		for _, workers := range []int{0, 2, 7, 64} {
+			got := calc.CalculateParallel(backlinks, outlinks, workers)
+			if len(got) != len(want) {
+				t.Fatalf("%s/%d workers: expected %d results, got %d", name, workers, len(want), len(got))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("%s/%d workers: result %d = %v, want %v", name, workers, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkCalculateSequential(b *testing.B) {
+	backlinks, outlinks := GeneratePowerLawGraph(10000, 8, 1)
+	calc := New()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		calc.Calculate(backlinks, outlinks)
+	}
+}
+
+func BenchmarkCalculateParallel(b *testing.B) {
+	backlinks, outlinks := GeneratePowerLawGraph(10000, 8, 1)
+	calc := New()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		calc.CalculateParallel(backlinks, outlinks, runtime.GOMAXPROCS(0))
+	}
+}
+
+func TestCalculateSubsetMatchesFull(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(500, 3, 9)
+	full := map[string]float64{}
+	for _, r := range New().Calculate(backlinks, outlinks) {
+		full[r.URL] = r.Rank
+	}
+
+	targets := []string{"page-3", "page-42", "page-499", "page-42", "not-in-graph"}
+	got := New().CalculateSubset(backlinks, outlinks, targets)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 results for distinct known targets, got %v", got)
+	}
+	for i, r := range got {
+		if r.Rank != full[r.URL] {
+			t.Errorf("%s: subset rank %v, full rank %v", r.URL, r.Rank, full[r.URL])
+		}
+		if i > 0 && got[i-1].Rank < r.Rank {
+			t.Errorf("subset results not sorted at %d", i)
+		}
+	}
+}
+
+func TestMaxMemoryBytesRejectsLargeGraph(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calc := New().SetMaxMemoryBytes(1)
+	if _, err := calc.CalculateContext(context.Background(), backlinks, outlinks); !errors.Is(err, ErrMemoryLimitExceeded) {
+		t.Fatalf("expected ErrMemoryLimitExceeded, got %v", err)
+	}
+	if got := calc.Calculate(backlinks, outlinks); len(got) != 0 {
+		t.Errorf("expected no results over the limit, got %d", len(got))
+	}
+
+	calc.SetMaxMemoryBytes(EstimateMemoryBytes(backlinks, outlinks))
+	got, err := calc.CalculateContext(context.Background(), backlinks, outlinks)
+	if err != nil {
+		t.Fatalf("unexpected error at the estimate: %v", err)
+	}
+	if len(got) != len(outlinks) {
+		t.Errorf("expected %d results, got %d", len(outlinks), len(got))
+	}
+}
+
+func TestCalculateContextCancelled(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := New().CalculateContext(ctx, backlinks, outlinks); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCalculateSparseMatchesCalculate(t *testing.T) {
+	sparseBacklinks, sparseOutlinks := sparseGraph(2_000)
+	sampleBacklinks, sampleOutlinks := sampleGraph()
+	graphs := []struct {
+		backlinks map[string][]string
+		outlinks  map[string]int
+	}{
+		{sparseBacklinks, sparseOutlinks},
+		{sampleBacklinks, sampleOutlinks},
+	}
+	calcs := []*Calculator{
+		New(),
+		New().SetRestartProbabilities(map[string]float64{"page-1": 0.5}).SetMaxRankCap(0.2),
+	}
+	for gi, g := range graphs {
+		for ci, calc := range calcs {
+			want := calc.Calculate(g.backlinks, g.outlinks)
+			got := calc.CalculateSparse(g.backlinks, g.outlinks)
+			if len(got) != len(want) {
+				t.Fatalf("graph %d calc %d: got %d results, want %d", gi, ci, len(got), len(want))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("graph %d calc %d: result %d = %v, want %v", gi, ci, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestPrecisionFloat32CloseToFloat64(t *testing.T) {
+	backlinks, outlinks := syntheticGraph(1_000, 5, 3)
+	want := ScoreMap(New().Calculate(backlinks, outlinks))
+	got := New().SetPrecision(Float32).Calculate(backlinks, outlinks)
+	if len(got) != len(want) {
+		t.Fatalf("got %d results, want %d", len(got), len(want))
+	}
+	for _, r := range got {
+		if rel := math.Abs(r.Rank-want[r.URL]) / want[r.URL]; rel > 1e-5 {
+			t.Errorf("%s: float32 rank %v differs from %v by %.2g", r.URL, r.Rank, want[r.URL], rel)
+		}
+	}
+	if New().SetPrecision(Precision(7)).precision != Float64 {
+		t.Error("unknown precision should be ignored")
+	}
+}
+
+func TestOutlinkNormalizerWeightsApplied(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calls := 0
+	// page-c links to page-a, page-b and page-d; send all of its rank to
+	// page-d and split every other page evenly.
+	normalizer := func(source string, targets []string, counts map[string]int) map[string]float64 {
+		calls++
+		weights := make(map[string]float64, len(targets))
+		for _, t := range targets {
+			if source != "page-c" || t == "page-d" {
+				weights[t] = 1
+			}
+		}
+		return weights
+	}
+	got := ScoreMap(New().SetOutlinkNormalizer(normalizer).Calculate(backlinks, outlinks))
+	if calls != 4 {
+		t.Errorf("normalizer called %d times, want once per linking page", calls)
+	}
+
+	// The same graph with page-c's other two links removed.
+	pruned := map[string][]string{
+		"page-a": {"page-b"},
+		"page-c": {"page-a", "page-d"},
+		"page-d": {"page-b", "page-c", "page-a"},
+	}
+	prunedOut := map[string]int{"page-a": 2, "page-b": 2, "page-c": 1, "page-d": 1}
+	want := ScoreMap(New().Calculate(pruned, prunedOut))
+	for url, rank := range want {
+		if math.Abs(got[url]-rank) > 1e-12 {
+			t.Errorf("%s: got %v, want %v", url, got[url], rank)
+		}
+	}
+
+	even := func(_ string, targets []string, _ map[string]int) map[string]float64 {
+		weights := make(map[string]float64, len(targets))
+		for _, t := range targets {
+			weights[t] = 2
+		}
+		return weights
+	}
+	plain := ScoreMap(New().Calculate(backlinks, outlinks))
+	for url, rank := range ScoreMap(New().SetOutlinkNormalizer(even).Calculate(backlinks, outlinks)) {
+		if math.Abs(plain[url]-rank) > 1e-12 {
+			t.Errorf("even split changed %s: %v -> %v", url, plain[url], rank)
+		}
+	}
+}
+
+func TestRankLoggerMatchesResults(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	var buf bytes.Buffer
+	results := New().SetIterations(20).SetRankLogger(csv.NewWriter(&buf)).Calculate(backlinks, outlinks)
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reparse log: %v", err)
+	}
+	if want := 20 * len(results); len(rows) != want {
+		t.Fatalf("expected %d rows, got %d", want, len(rows))
+	}
+	final := make(map[string]float64)
+	for _, row := range rows {
+		iteration, err := strconv.Atoi(row[0])
+		if err != nil {
+			t.Fatalf("bad iteration in %v: %v", row, err)
+		}
+		if iteration != 20 {
+			continue
+		}
+		rank, err := strconv.ParseFloat(row[2], 64)
+		if err != nil {
+			t.Fatalf("bad rank in %v: %v", row, err)
+		}
+		final[row[1]] = rank
+	}
+	for _, r := range results {
+		if final[r.URL] != r.Rank {
+			t.Errorf("logged %s = %v, result %v", r.URL, final[r.URL], r.Rank)
+		}
+	}
+}
+
+func TestRankLoggerKeepsObserver(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calc := New().SetIterations(5)
+	var observed int
+	calc.observe = func(iteration int, _ []string, _ []float64) { observed = iteration }
+	var buf bytes.Buffer
+	calc.SetRankLogger(csv.NewWriter(&buf))
+
+	// CalculateFractional adds its own observer on top of both.
+	results := calc.CalculateFractional(backlinks, outlinks, 2.5)
+	if observed != 3 {
+		t.Errorf("observer saw %d iterations, want 3", observed)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reparse log: %v", err)
+	}
+	if want := 3 * len(results); len(rows) != want {
+		t.Errorf("logged %d rows, want %d", len(rows), want)
+	}
+}
+
+func TestUndirectedMatchesDirectedOnSymmetricGraph(t *testing.T) {
+	backlinks := map[string][]string{
+		"a": {"b", "c"},
+		"b": {"a"},
+		"c": {"a", "d"},
+		"d": {"c"},
+	}
+	outlinks := map[string]int{"a": 2, "b": 1, "c": 2, "d": 1}
+	directed := New().Calculate(backlinks, outlinks)
+	undirected := New().SetUndirected(true).Calculate(backlinks, outlinks)
+	if len(directed) != len(undirected) {
+		t.Fatalf("result counts differ: %d vs %d", len(directed), len(undirected))
+	}
+	for i := range directed {
+		if directed[i] != undirected[i] {
+			t.Errorf("result %d: directed %v, undirected %v", i, directed[i], undirected[i])
+		}
+	}
+}
+
+func TestUndirectedAddsReverseLinks(t *testing.T) {
+	// A one-way chain becomes the symmetric graph above.
+	backlinks := map[string][]string{"b": {"a"}, "c": {"a"}, "d": {"c"}}
+	outlinks := map[string]int{"a": 2, "c": 1}
+	got := New().SetUndirected(true).Calculate(backlinks, outlinks)
+	want := New().Calculate(map[string][]string{
+		"a": {"b", "c"},
+		"b": {"a"},
+		"c": {"a", "d"},
+		"d": {"c"},
+	}, map[string]int{"a": 2, "b": 1, "c": 2, "d": 1})
+	for i := range want {
+		if math.Abs(got[i].Rank-want[i].Rank) > 1e-12 || got[i].URL != want[i].URL {
+			t.Errorf("result %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+	if len(backlinks["a"]) != 0 || outlinks["b"] != 0 {
+		t.Error("SetUndirected modified the caller's maps")
+	}
+}
+
+func TestAbsorbingNodeKeepsItsRank(t *testing.T) {
+	backlinks := map[string][]string{
+		"checkout": {"home", "product"},
+		"product":  {"home"},
+		"thanks":   {"checkout"},
+		"home":     {"thanks"},
+	}
+	outlinks := map[string]int{"home": 2, "product": 1, "checkout": 1, "thanks": 1}
+	calc := New().SetAbsorbingNodes([]string{"checkout"})
+	ranks := ScoreMap(calc.Calculate(backlinks, outlinks))
+
+	base := (1 - calc.Damping()) / float64(len(ranks))
+	if math.Abs(ranks["thanks"]-base) > 1e-12 {
+		t.Errorf("page linked only from the absorbing node has rank %v, want teleport base %v", ranks["thanks"], base)
+	}
+	if ranks["checkout"] <= ranks["product"] {
+		t.Errorf("absorbing node should still accumulate rank: checkout %v, product %v", ranks["checkout"], ranks["product"])
+	}
+	plain := ScoreMap(New().Calculate(backlinks, outlinks))
+	if plain["thanks"] <= base {
+		t.Errorf("without absorbing nodes thanks should get link rank, got %v", plain["thanks"])
+	}
+}
+
+func TestToleranceStopsEarly(t *testing.T) {
+	backlinks, outlinks := syntheticGraph(100, 4, 7)
+	var ran int
+	calc := New().SetIterations(500).SetTolerance(1e-8)
+	calc.observe = func(iteration int, _ []string, _ []float64) { ran = iteration }
+	got := calc.Calculate(backlinks, outlinks)
+	if ran == 0 || ran >= 500 {
+		t.Fatalf("expected an early stop, ran %d iterations", ran)
+	}
+	want := ScoreMap(New().SetIterations(500).Calculate(backlinks, outlinks))
+	for _, r := range got {
+		if math.Abs(r.Rank-want[r.URL]) > 1e-7 {
+			t.Errorf("%s: %v after early stop, %v after 500 iterations", r.URL, r.Rank, want[r.URL])
+		}
+	}
+}
+
+func TestCalculateWarmStartContinuesIteration(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(300, 3, 8)
+	start := ScoreMap(New().SetIterations(50).Calculate(backlinks, outlinks))
+	warm := New().SetIterations(5).CalculateWarmStart(backlinks, outlinks, start)
+	cold := ScoreMap(New().SetIterations(55).Calculate(backlinks, outlinks))
+	if len(warm) != len(cold) {
+		t.Fatalf("got %d results, want %d", len(warm), len(cold))
+	}
+	for _, r := range warm {
+		if math.Abs(r.Rank-cold[r.URL]) > 1e-12 {
+			t.Errorf("%s: warm %v, cold %v", r.URL, r.Rank, cold[r.URL])
+		}
+	}
+
+	// A page the starting ranks do not know about starts at 1/N.
+	backlinks["fresh"] = []string{"page-0"}
+	outlinks["page-0"]++
+	one := New().SetIterations(1)
+	got := ScoreMap(one.CalculateWarmStart(backlinks, outlinks, map[string]float64{}))
+	want := ScoreMap(one.Calculate(backlinks, outlinks))
+	if got["fresh"] != want["fresh"] {
+		t.Errorf("fresh page: warm %v, cold %v", got["fresh"], want["fresh"])
+	}
+}
+
+func TestMaxNodesAndEdges(t *testing.T) {
+	backlinks, outlinks := syntheticGraph(50, 3, 1)
+	edges := EdgeCount(backlinks)
+	limits := map[string]*Calculator{
+		"nodes": New().SetMaxNodes(49),
+		"edges": New().SetMaxEdges(edges - 1),
+		// The size limits are checked before the memory estimate.
+		"nodes before memory": New().SetMaxNodes(49).SetMaxMemoryBytes(1),
+	}
+	for name, calc := range limits {
+		_, err := calc.CalculateContext(context.Background(), backlinks, outlinks)
+		if !errors.Is(err, ErrGraphTooLarge) {
+			t.Errorf("%s: expected ErrGraphTooLarge, got %v", name, err)
+		}
+		if got := calc.Calculate(backlinks, outlinks); len(got) != 0 {
+			t.Errorf("%s: Calculate returned %d results over the limit", name, len(got))
+		}
+	}
+	for _, calc := range []*Calculator{New().SetMaxNodes(50).SetMaxEdges(edges), New()} {
+		if got := calc.Calculate(backlinks, outlinks); len(got) != 50 {
+			t.Errorf("%s: expected 50 results within the limits, got %d", calc, len(got))
+		}
+	}
+}
+
+func TestAdaptiveDampingConvergesToInitial(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	want := ScoreMap(New().SetIterations(200).Calculate(backlinks, outlinks))
+
+	calc := New().SetIterations(200).SetAdaptiveDamping(0.85, 0.5, 0.99)
+	got := ScoreMap(calc.Calculate(backlinks, outlinks))
+	for url, w := range want {
+		if math.Abs(got[url]-w) > 1e-4 {
+			t.Errorf("%s: adaptive rank %g, fixed %g", url, got[url], w)
+		}
+	}
+
+	// The first step spreads the ranks out from uniform, so the second one
+	// runs at a higher damping than the fixed calculator's.
+	two := ScoreMap(calc.Clone().SetIterations(2).Calculate(backlinks, outlinks))
+	fixed := ScoreMap(New().SetIterations(2).Calculate(backlinks, outlinks))
+	if reflect.DeepEqual(two, fixed) {
+		t.Errorf("adaptive damping left the second iteration unchanged: %v", two)
+	}
+}
+
+func TestGaussSeidelMatchesJacobi(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	ran := make(map[IterationMethod]int)
+	for _, method := range []IterationMethod{Jacobi, GaussSeidel} {
+		calc := New().SetIterations(1000).SetTolerance(1e-10).SetIterationMethod(method)
+		calc.observe = func(iteration int, _ []string, _ []float64) { ran[method] = iteration }
+		calc.Calculate(backlinks, outlinks)
+	}
+	if ran[GaussSeidel] >= ran[Jacobi] {
+		t.Errorf("Gauss-Seidel took %d iterations, Jacobi %d", ran[GaussSeidel], ran[Jacobi])
+	}
+
+	// The power-law graph has pages without outlinks, so rank leaks away.
+	leaky, leakyOut := GeneratePowerLawGraph(300, 3, 5)
+	for _, graph := range []struct {
+		name string
+		b    map[string][]string
+		o    map[string]int
+	}{{"sample", backlinks, outlinks}, {"leaky", leaky, leakyOut}} {
+		name, b, o := graph.name, graph.b, graph.o
+		want := ScoreMap(New().SetIterations(500).Calculate(b, o))
+		got := New().SetIterations(500).SetIterationMethod(GaussSeidel).CalculateParallel(b, o, 4)
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d results, want %d", name, len(got), len(want))
+		}
+		for _, r := range got {
+			if math.Abs(r.Rank-want[r.URL]) > 1e-12 {
+				t.Errorf("%s: %s: Gauss-Seidel %v, Jacobi %v", name, r.URL, r.Rank, want[r.URL])
+			}
+		}
+	}
+}
+
+func TestFullyCorrectFormulation(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	// page-e is linked from page-a and links nowhere.
+	backlinks["page-e"] = []string{"page-a"}
+	outlinks["page-a"]++
+	outlinks["page-e"] = 0
+
+	for _, method := range []IterationMethod{Jacobi, GaussSeidel} {
+		calc := New().SetIterations(500).SetFullyCorrectFormulation(true).SetIterationMethod(method)
+		results := calc.Calculate(backlinks, outlinks)
+		rank := ScoreMap(results)
+		var sum float64
+		for _, r := range results {
+			sum += r.Rank
+		}
+		if math.Abs(sum-1) > 1e-12 {
+			t.Errorf("%s: ranks sum to %.15f, want 1", method, sum)
+		}
+
+		// Multiply by M = d*A + (1-d)*E/N directly, with page-e's column
+		// of A uniform.
+		urls := collectURLs(backlinks, outlinks)
+		n := float64(len(urls))
+		d := calc.Damping()
+		for _, target := range urls {
+			want := (1 - d) / n
+			for _, src := range urls {
+				if outlinks[src] == 0 {
+					want += d * rank[src] / n
+				}
+			}
+			for _, src := range backlinks[target] {
+				want += d * rank[src] / float64(outlinks[src])
+			}
+			if math.Abs(rank[target]-want) > 1e-12 {
+				t.Errorf("%s: %s has rank %v, but M·r gives %v", method, target, rank[target], want)
+			}
+		}
+	}
+
+	// Without dangling pages the formulations agree.
+	backlinks, outlinks = sampleGraph()
+	want := ScoreMap(New().Calculate(backlinks, outlinks))
+	for _, r := range New().SetFullyCorrectFormulation(true).Calculate(backlinks, outlinks) {
+		if math.Abs(r.Rank-want[r.URL]) > 1e-15 {
+			t.Errorf("%s: %v, want %v", r.URL, r.Rank, want[r.URL])
+		}
+	}
+}
+
+func TestFullyCorrectFormulationWithAbsorbingAndSpam(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	// page-e, page-f and page-g link nowhere; page-f is also absorbing and
+	// page-g fully spam, so neither spreads anything, while page-e spreads
+	// half its rank. page-b keeps its links but absorbs.
+	backlinks["page-e"] = []string{"page-a"}
+	backlinks["page-f"] = []string{"page-c"}
+	backlinks["page-g"] = []string{"page-d"}
+	outlinks["page-a"]++
+	outlinks["page-c"]++
+	outlinks["page-d"]++
+	spam := map[string]float64{"page-e": 0.5, "page-g": 1, "page-d": 0.25}
+	absorbing := map[string]bool{"page-b": true, "page-f": true}
+	keep := func(url string) float64 {
+		if absorbing[url] {
+			return 0
+		}
+		return 1 - spam[url]
+	}
+
+	for _, method := range []IterationMethod{Jacobi, GaussSeidel} {
+		calc := New().SetIterations(500).SetFullyCorrectFormulation(true).SetIterationMethod(method).
+			SetAbsorbingNodes([]string{"page-b", "page-f"}).SetSpamScores(spam)
+		results := calc.Calculate(backlinks, outlinks)
+		rank := ScoreMap(results)
+
+		urls := collectURLs(backlinks, outlinks)
+		n := float64(len(urls))
+		d := calc.Damping()
+		for _, target := range urls {
+			want := (1 - d) / n
+			for _, src := range urls {
+				if outlinks[src] == 0 {
+					want += d * keep(src) * rank[src] / n
+				}
+			}
+			for _, src := range backlinks[target] {
+				want += d * keep(src) * rank[src] / float64(outlinks[src])
+			}
+			if math.Abs(rank[target]-want) > 1e-12 {
+				t.Errorf("%s: %s has rank %v, want %v", method, target, rank[target], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Package parquetio reads and writes PageRank results as Parquet files,
+// with a UTF-8 "url" column and a DOUBLE "rank" column.
+package parquetio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/Harsh-Pratap-Singh/Search_Engine/pagerank"
+)
+
+type row struct {
+	URL  string  `parquet:"name=url, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Rank float64 `parquet:"name=rank, type=DOUBLE"`
+}
+
+// WriteResultsParquet writes results to w as a single Parquet file.
+func WriteResultsParquet(w io.Writer, results []pagerank.Result) error {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(row), 1)
+	if err != nil {
+		return fmt.Errorf("parquetio: create writer: %w", err)
+	}
+	for _, r := range results {
+		if err := pw.Write(row{URL: r.URL, Rank: r.Rank}); err != nil {
+			return fmt.Errorf("parquetio: write %s: %w", r.URL, err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		return fmt.Errorf("parquetio: finish file: %w", err)
+	}
+	return nil
+}
+
+// ReadResultsParquet reads a file written by WriteResultsParquet. Parquet
+// keeps its metadata at the end of the file, so r is read into memory
+// first.
+func ReadResultsParquet(r io.Reader) ([]pagerank.Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("parquetio: read file: %w", err)
+	}
+	pr, err := reader.NewParquetReader(memFile{bytes.NewReader(data)}, new(row), 1)
+	if err != nil {
+		return nil, fmt.Errorf("parquetio: open file: %w", err)
+	}
+	defer pr.ReadStop()
+
+	rows := make([]row, pr.GetNumRows())
+	if err := pr.Read(&rows); err != nil {
+		return nil, fmt.Errorf("parquetio: read rows: %w", err)
+	}
+	results := make([]pagerank.Result, len(rows))
+	for i, r := range rows {
+		results[i] = pagerank.Result{URL: r.URL, Rank: r.Rank}
+	}
+	return results, nil
+}
+
+// memFile is a read-only source.ParquetFile over an in-memory file.
+type memFile struct {
+	*bytes.Reader
+}
+
+var errReadOnly = errors.New("parquetio: file is read-only")
+
+func (f memFile) Open(string) (source.ParquetFile, error) {
+	data := make([]byte, f.Size())
+	f.ReadAt(data, 0)
+	return memFile{bytes.NewReader(data)}, nil
+}
+
+func (memFile) Create(string) (source.ParquetFile, error) { return nil, errReadOnly }
+func (memFile) Write([]byte) (int, error)                 { return 0, errReadOnly }
+func (memFile) Close() error                              { return nil }
@@ -0,0 +1,41 @@
+package parquetio
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/Harsh-Pratap-Singh/Search_Engine/pagerank"
+)
+
+func TestResultsParquetRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	results := make([]pagerank.Result, 1000)
+	for i := range results {
+		results[i] = pagerank.Result{URL: fmt.Sprintf("https://example.com/%d/ünï", i), Rank: rng.Float64() / 1000}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteResultsParquet(&buf, results); err != nil {
+		t.Fatalf("WriteResultsParquet: %v", err)
+	}
+	got, err := ReadResultsParquet(&buf)
+	if err != nil {
+		t.Fatalf("ReadResultsParquet: %v", err)
+	}
+	if len(got) != len(results) {
+		t.Fatalf("read %d results, wrote %d", len(got), len(results))
+	}
+	for i := range results {
+		if got[i] != results[i] {
+			t.Errorf("result %d = %v, want %v", i, got[i], results[i])
+		}
+	}
+}
+
+func TestReadResultsParquetRejectsGarbage(t *testing.T) {
+	if _, err := ReadResultsParquet(bytes.NewReader([]byte("not parquet"))); err == nil {
+		t.Error("expected an error for a non-Parquet file")
+	}
+}
@@ -0,0 +1,50 @@
+package pagerank
+
+import (
+	"math"
+	"sort"
+)
+
+// Percentile returns the rank at fraction p of the way from the lowest
+// rank (p = 0) to the highest (p = 1), interpolating linearly between
+// neighbouring ranks. p is clamped to [0, 1]; empty results give NaN.
+func Percentile(results []Result, p float64) float64 {
+	if len(results) == 0 {
+		return math.NaN()
+	}
+	ranks := make([]float64, len(results))
+	for i, r := range results {
+		ranks[i] = r.Rank
+	}
+	sort.Float64s(ranks)
+	p = math.Max(0, math.Min(1, p))
+	pos := p * float64(len(ranks)-1)
+	lo := int(math.Floor(pos))
+	if lo == len(ranks)-1 {
+		return ranks[lo]
+	}
+	frac := pos - float64(lo)
+	return ranks[lo] + frac*(ranks[lo+1]-ranks[lo])
+}
+
+// PercentileRank returns the fraction of results whose rank is at most
+// url's, or 0 if url is not among them.
+func PercentileRank(results []Result, url string) float64 {
+	rank, found := 0.0, false
+	for _, r := range results {
+		if r.URL == url {
+			rank, found = r.Rank, true
+			break
+		}
+	}
+	if !found {
+		return 0
+	}
+	var below int
+	for _, r := range results {
+		if r.Rank <= rank {
+			below++
+		}
+	}
+	return float64(below) / float64(len(results))
+}
@@ -0,0 +1,45 @@
+package pagerank
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	results := New().Calculate(syntheticGraph(50, 3, 2))
+	min, max := results[len(results)-1].Rank, results[0].Rank
+	if got := Percentile(results, 1); got != max {
+		t.Errorf("Percentile(1) = %v, want max %v", got, max)
+	}
+	if got := Percentile(results, 0); got != min {
+		t.Errorf("Percentile(0) = %v, want min %v", got, min)
+	}
+
+	simple := []Result{{"a", 1}, {"b", 3}, {"c", 2}}
+	if got := Percentile(simple, 0.75); math.Abs(got-2.5) > 1e-12 {
+		t.Errorf("Percentile(0.75) = %v, want 2.5", got)
+	}
+	if !math.IsNaN(Percentile(nil, 0.5)) {
+		t.Error("Percentile of no results should be NaN")
+	}
+}
+
+func TestPercentileRankMonotone(t *testing.T) {
+	results := New().Calculate(syntheticGraph(50, 3, 2))
+	sort.Sort(ByRankAsc(results))
+	prev := 0.0
+	for _, r := range results {
+		p := PercentileRank(results, r.URL)
+		if p < prev {
+			t.Fatalf("PercentileRank(%s) = %v dropped below %v", r.URL, p, prev)
+		}
+		prev = p
+	}
+	if prev != 1 {
+		t.Errorf("top page has percentile rank %v, want 1", prev)
+	}
+	if got := PercentileRank(results, "missing"); got != 0 {
+		t.Errorf("unknown URL got %v", got)
+	}
+}
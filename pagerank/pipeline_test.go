@@ -0,0 +1,109 @@
+package pagerank
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestEndToEndPipeline crawls a small site, ranks it, and round-trips the
+// results through a JSON file.
+func TestEndToEndPipeline(t *testing.T) {
+	site := map[string][]string{
+		"/":    {"/a", "/b", "/hub"},
+		"/a":   {"/hub", "/b"},
+		"/b":   {"/hub"},
+		"/c":   {"/hub", "/a"},
+		"/hub": {"/c"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		links, ok := site[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><body>")
+		for _, l := range links {
+			fmt.Fprintf(w, `<a href="%s">%s</a>`, l, l)
+		}
+		fmt.Fprint(w, "</body></html>")
+	}))
+	defer srv.Close()
+
+	g := NewGraph()
+	frontier := []string{srv.URL + "/"}
+	seen := map[string]bool{srv.URL + "/": true}
+	for len(frontier) > 0 {
+		page := frontier[0]
+		frontier = frontier[1:]
+		resp, err := srv.Client().Get(page)
+		if err != nil {
+			t.Fatalf("GET %s: %v", page, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", page, err)
+		}
+
+		links, err := ExtractLinksFromHTML(page, bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ExtractLinksFromHTML(%s): %v", page, err)
+		}
+		for _, l := range links {
+			if !seen[l] {
+				seen[l] = true
+				frontier = append(frontier, l)
+			}
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if err := g.AddEdgesFromHTMLResponse(resp); err != nil {
+			t.Fatalf("AddEdgesFromHTMLResponse(%s): %v", page, err)
+		}
+	}
+	if g.NodeCount() != len(site) {
+		t.Fatalf("crawled %d pages, want %d", g.NodeCount(), len(site))
+	}
+
+	backlinks, outlinks := g.Build()
+	results := New().Calculate(backlinks, outlinks)
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	data, err := marshalResultsJSON(results)
+	if err != nil {
+		t.Fatalf("marshalResultsJSON: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := unmarshalResultsJSON(data)
+	if err != nil {
+		t.Fatalf("unmarshalResultsJSON: %v", err)
+	}
+	if len(reloaded) != len(results) {
+		t.Fatalf("reloaded %d results, want %d", len(reloaded), len(results))
+	}
+
+	mostLinked, most := "", 0
+	for url, sources := range backlinks {
+		if len(sources) > most {
+			mostLinked, most = url, len(sources)
+		}
+	}
+	if mostLinked != srv.URL+"/hub" {
+		t.Fatalf("fixture broken: most inlinks on %s", mostLinked)
+	}
+	if reloaded[0].URL != mostLinked {
+		t.Errorf("top page is %s, want %s", reloaded[0].URL, mostLinked)
+	}
+}
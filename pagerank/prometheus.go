@@ -0,0 +1,80 @@
+package pagerank
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// prometheusBuckets are the upper bounds WritePrometheusHistogram counts
+// ranks into, besides +Inf.
+var prometheusBuckets = []float64{0.0001, 0.001, 0.01, 0.1, 1}
+
+var metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// WritePrometheusHistogram writes the distribution of ranks as a histogram
+// named metricName in the Prometheus text exposition format, with
+// cumulative buckets at 0.0001, 0.001, 0.01, 0.1 and 1.
+func WritePrometheusHistogram(w io.Writer, results []Result, metricName string) error {
+	if !metricNamePattern.MatchString(metricName) {
+		return fmt.Errorf("pagerank: invalid metric name %q", metricName)
+	}
+	counts := make([]int, len(prometheusBuckets))
+	var sum float64
+	for _, r := range results {
+		sum += r.Rank
+		for i, le := range prometheusBuckets {
+			if r.Rank <= le {
+				counts[i]++
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# HELP %s Distribution of PageRank values.\n", metricName)
+	fmt.Fprintf(bw, "# TYPE %s histogram\n", metricName)
+	for i, le := range prometheusBuckets {
+		fmt.Fprintf(bw, "%s_bucket{le=%q} %d\n", metricName, formatMetricValue(le), counts[i])
+	}
+	fmt.Fprintf(bw, "%s_bucket{le=\"+Inf\"} %d\n", metricName, len(results))
+	fmt.Fprintf(bw, "%s_sum %s\n", metricName, formatMetricValue(sum))
+	fmt.Fprintf(bw, "%s_count %d\n", metricName, len(results))
+	return bw.Flush()
+}
+
+// WritePrometheusGauge writes the ranks of the topK highest-ranked pages
+// as a gauge named metricName, one sample per page labelled with its url.
+// Pages with equal rank are taken in URL order, so the selection does not
+// change between scrapes.
+func WritePrometheusGauge(w io.Writer, results []Result, metricName string, topK int) error {
+	if !metricNamePattern.MatchString(metricName) {
+		return fmt.Errorf("pagerank: invalid metric name %q", metricName)
+	}
+	top := append([]Result(nil), results...)
+	sort.Stable(ByRankDesc(top))
+	if topK < 0 {
+		topK = 0
+	}
+	if topK < len(top) {
+		top = top[:topK]
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# HELP %s PageRank of the highest-ranked pages.\n", metricName)
+	fmt.Fprintf(bw, "# TYPE %s gauge\n", metricName)
+	for _, r := range top {
+		fmt.Fprintf(bw, "%s{url=\"%s\"} %s\n", metricName, labelEscaper.Replace(r.URL), formatMetricValue(r.Rank))
+	}
+	return bw.Flush()
+}
+
+// labelEscaper escapes a label value as the exposition format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatMetricValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,86 @@
+package pagerank
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestWritePrometheusHistogram(t *testing.T) {
+	results := New().Calculate(GeneratePowerLawGraph(500, 3, 2))
+	var buf bytes.Buffer
+	if err := WritePrometheusHistogram(&buf, results, "giggle_pagerank"); err != nil {
+		t.Fatalf("WritePrometheusHistogram: %v", err)
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "giggle_pagerank",
+		Help:    "Distribution of PageRank values.",
+		Buckets: prometheusBuckets,
+	})
+	reg.MustRegister(h)
+	for _, r := range results {
+		h.Observe(r.Rank)
+	}
+	if err := testutil.GatherAndCompare(reg, &buf, "giggle_pagerank"); err != nil {
+		t.Errorf("output does not match the client library's histogram: %v", err)
+	}
+
+	if err := WritePrometheusHistogram(&buf, results, "bad name"); err == nil {
+		t.Error("expected an error for an invalid metric name")
+	}
+}
+
+func TestWritePrometheusGauge(t *testing.T) {
+	results := New().Calculate(sampleGraph())
+	results = append(results, Result{URL: `odd "page"`, Rank: 0})
+	var buf bytes.Buffer
+	if err := WritePrometheusGauge(&buf, results, "giggle_top_rank", 3); err != nil {
+		t.Fatalf("WritePrometheusGauge: %v", err)
+	}
+	if n := strings.Count(buf.String(), "giggle_top_rank{"); n != 3 {
+		t.Errorf("wrote %d samples, want 3:\n%s", n, buf.String())
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "giggle_top_rank",
+		Help: "PageRank of the highest-ranked pages.",
+	}, []string{"url"})
+	reg.MustRegister(g)
+	for _, r := range results[:3] {
+		g.WithLabelValues(r.URL).Set(r.Rank)
+	}
+	if err := testutil.GatherAndCompare(reg, &buf, "giggle_top_rank"); err != nil {
+		t.Errorf("output does not match the client library's gauge: %v", err)
+	}
+
+	buf.Reset()
+	if err := WritePrometheusGauge(&buf, results, "giggle_top_rank", 10); err != nil {
+		t.Fatal(err)
+	}
+	g.WithLabelValues(`odd "page"`).Set(0)
+	for _, r := range results[3:] {
+		g.WithLabelValues(r.URL).Set(r.Rank)
+	}
+	if err := testutil.GatherAndCompare(reg, &buf, "giggle_top_rank"); err != nil {
+		t.Errorf("escaped label does not round-trip: %v", err)
+	}
+}
+
+func TestWritePrometheusGaugeBreaksTiesByURL(t *testing.T) {
+	results := []Result{{URL: "c", Rank: 0.5}, {URL: "a", Rank: 0.5}, {URL: "b", Rank: 0.5}}
+	var buf bytes.Buffer
+	if err := WritePrometheusGauge(&buf, results, "giggle_top_rank", 2); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	a, b := strings.Index(out, `url="a"`), strings.Index(out, `url="b"`)
+	if a < 0 || b < a || strings.Contains(out, `url="c"`) {
+		t.Errorf("expected a then b among tied pages:\n%s", out)
+	}
+}
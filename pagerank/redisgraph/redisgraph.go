@@ -0,0 +1,110 @@
+// Package redisgraph reads PageRank link graphs stored in Redis, where the
+// backlinks of each page are a set under "<prefix><url>".
+package redisgraph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Harsh-Pratap-Singh/Search_Engine/pagerank"
+)
+
+const (
+	DefaultKeyPrefix = "backlinks:"
+
+	cacheSize = 4096
+	scanCount = 1000
+)
+
+// RedisGraphDB implements pagerank.BatchGraphDB on top of a Redis client.
+// Backlink sets are cached in a local LRU so repeated lookups do not go
+// back to Redis.
+type RedisGraphDB struct {
+	client    *redis.Client
+	keyPrefix string
+
+	mu    sync.Mutex
+	cache *lru
+}
+
+var _ pagerank.BatchGraphDB = (*RedisGraphDB)(nil)
+
+func NewRedisGraphDB(client *redis.Client, keyPrefix string) *RedisGraphDB {
+	if keyPrefix == "" {
+		keyPrefix = DefaultKeyPrefix
+	}
+	return &RedisGraphDB{
+		client:    client,
+		keyPrefix: keyPrefix,
+		cache:     newLRU(cacheSize),
+	}
+}
+
+// Nodes lists every page that has a backlinks key. Pages that only appear
+// as link sources are discovered through the backlink sets themselves.
+func (db *RedisGraphDB) Nodes() ([]string, error) {
+	ctx := context.Background()
+	var nodes []string
+	iter := db.client.Scan(ctx, 0, db.keyPrefix+"*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		nodes = append(nodes, strings.TrimPrefix(iter.Val(), db.keyPrefix))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("redisgraph: scan %s*: %w", db.keyPrefix, err)
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+func (db *RedisGraphDB) Backlinks(url string) ([]string, error) {
+	m, err := db.BacklinksBatch([]string{url})
+	if err != nil {
+		return nil, err
+	}
+	return m[url], nil
+}
+
+// BacklinksBatch fetches the backlink sets for urls, issuing a single
+// pipelined round of SMEMBERS for the ones not already cached.
+func (db *RedisGraphDB) BacklinksBatch(urls []string) (map[string][]string, error) {
+	out := make(map[string][]string, len(urls))
+	var missing []string
+
+	db.mu.Lock()
+	for _, url := range urls {
+		if sources, ok := db.cache.get(url); ok {
+			out[url] = sources
+		} else {
+			missing = append(missing, url)
+		}
+	}
+	db.mu.Unlock()
+	if len(missing) == 0 {
+		return out, nil
+	}
+
+	ctx := context.Background()
+	pipe := db.client.Pipeline()
+	cmds := make([]*redis.StringSliceCmd, len(missing))
+	for i, url := range missing {
+		cmds[i] = pipe.SMembers(ctx, db.keyPrefix+url)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("redisgraph: smembers: %w", err)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for i, url := range missing {
+		sources := cmds[i].Val()
+		sort.Strings(sources)
+		db.cache.add(url, sources)
+		out[url] = sources
+	}
+	return out, nil
+}
@@ -0,0 +1,122 @@
+package redisgraph
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Harsh-Pratap-Singh/Search_Engine/pagerank"
+)
+
+func sampleBacklinks() map[string][]string {
+	return map[string][]string{
+		"page-a": {"page-b", "page-c"},
+		"page-b": {"page-c"},
+		"page-c": {"page-a", "page-d"},
+		"page-d": {"page-a", "page-b", "page-c"},
+	}
+}
+
+func newTestDB(t *testing.T) (*RedisGraphDB, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	for url, sources := range sampleBacklinks() {
+		if _, err := mr.SAdd(DefaultKeyPrefix+url, sources...); err != nil {
+			t.Fatalf("seed %s: %v", url, err)
+		}
+	}
+	return NewRedisGraphDB(client, ""), mr
+}
+
+func TestNodesAndBacklinks(t *testing.T) {
+	db, _ := newTestDB(t)
+
+	nodes, err := db.Nodes()
+	if err != nil {
+		t.Fatalf("Nodes: %v", err)
+	}
+	if len(nodes) != 4 {
+		t.Fatalf("expected 4 nodes, got %v", nodes)
+	}
+
+	got, err := db.Backlinks("page-d")
+	if err != nil {
+		t.Fatalf("Backlinks: %v", err)
+	}
+	want := []string{"page-a", "page-b", "page-c"}
+	if len(got) != len(want) {
+		t.Fatalf("backlinks for page-d = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("backlinks for page-d = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBacklinksServedFromCache(t *testing.T) {
+	db, mr := newTestDB(t)
+
+	if _, err := db.Backlinks("page-a"); err != nil {
+		t.Fatalf("Backlinks: %v", err)
+	}
+	mr.Close()
+
+	got, err := db.Backlinks("page-a")
+	if err != nil {
+		t.Fatalf("cached lookup should not reach Redis: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected 2 cached backlinks, got %v", got)
+	}
+	if _, err := db.Backlinks("page-b"); err == nil {
+		t.Error("uncached lookup with Redis down should fail")
+	}
+}
+
+func TestCalculateFromRedis(t *testing.T) {
+	db, _ := newTestDB(t)
+
+	backlinks := sampleBacklinks()
+	outlinks := map[string]int{}
+	for _, sources := range backlinks {
+		for _, src := range sources {
+			outlinks[src]++
+		}
+	}
+	want := pagerank.New().Calculate(backlinks, outlinks)
+
+	got, err := pagerank.New().CalculateFromDB(db)
+	if err != nil {
+		t.Fatalf("CalculateFromDB: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].URL != want[i].URL {
+			t.Errorf("result %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLRUEvictsOldest(t *testing.T) {
+	c := newLRU(2)
+	c.add("a", []string{"1"})
+	c.add("b", []string{"2"})
+	c.get("a")
+	c.add("c", []string{"3"})
+	if _, ok := c.get("b"); ok {
+		t.Error("least recently used entry should have been evicted")
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Error("recently used entry should survive")
+	}
+	if c.len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.len())
+	}
+}
@@ -0,0 +1,64 @@
+package pagerank
+
+import "sort"
+
+// RerankWithSimilarity blends PageRank with a caller-supplied content
+// similarity per URL: alpha*rank/maxRank + (1-alpha)*similarities[url].
+// The returned results carry the blended score in Rank and are sorted by
+// it. query names the search the similarities belong to; only the
+// similarities themselves affect the score. URLs without a similarity
+// score count as 0.
+func RerankWithSimilarity(results []Result, query string, similarities map[string]float64, alpha float64) []Result {
+	var max float64
+	for _, r := range results {
+		if r.Rank > max {
+			max = r.Rank
+		}
+	}
+
+	out := make([]Result, len(results))
+	for i, r := range results {
+		var norm float64
+		if max > 0 {
+			norm = r.Rank / max
+		}
+		out[i] = Result{URL: r.URL, Rank: alpha*norm + (1-alpha)*similarities[r.URL]}
+	}
+	sort.Sort(ByRankDesc(out))
+	return out
+}
+
+type ClickFeedback struct {
+	URL         string
+	Clicks      int
+	Impressions int
+}
+
+// ApplyClickFeedback blends PageRank with click-through rate:
+// alpha*rank/maxRank + (1-alpha)*ctr/maxCTR, where ctr is clicks over
+// impressions. Entries for the same URL are summed, and URLs without
+// impressions count as 0. The returned results carry the blended score in
+// Rank and are sorted by it.
+func ApplyClickFeedback(results []Result, feedback []ClickFeedback, alpha float64) []Result {
+	clicks := make(map[string]int)
+	impressions := make(map[string]int)
+	for _, f := range feedback {
+		clicks[f.URL] += f.Clicks
+		impressions[f.URL] += f.Impressions
+	}
+	ctr := make(map[string]float64, len(impressions))
+	var maxCTR float64
+	for url, n := range impressions {
+		if n <= 0 || clicks[url] <= 0 {
+			continue
+		}
+		ctr[url] = float64(clicks[url]) / float64(n)
+		if ctr[url] > maxCTR {
+			maxCTR = ctr[url]
+		}
+	}
+	for url := range ctr {
+		ctr[url] /= maxCTR
+	}
+	return RerankWithSimilarity(results, "", ctr, alpha)
+}
@@ -0,0 +1,58 @@
+package pagerank
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRerankWithSimilarity(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	results := New().Calculate(backlinks, outlinks)
+	similarities := map[string]float64{
+		"page-b": 0.9,
+		"page-a": 0.6,
+		"page-d": 0.3,
+		"page-c": 0.1,
+	}
+
+	bySimilarity := RerankWithSimilarity(results, "golang", similarities, 0)
+	for i, want := range []string{"page-b", "page-a", "page-d", "page-c"} {
+		if bySimilarity[i].URL != want {
+			t.Errorf("alpha=0: position %d = %s, want %s", i, bySimilarity[i].URL, want)
+		}
+	}
+
+	byRank := RerankWithSimilarity(results, "golang", similarities, 1)
+	for i := range results {
+		if byRank[i].URL != results[i].URL {
+			t.Errorf("alpha=1: position %d = %s, want %s", i, byRank[i].URL, results[i].URL)
+		}
+	}
+	if byRank[0].Rank != 1 {
+		t.Errorf("alpha=1: top score should be the normalized rank 1, got %v", byRank[0].Rank)
+	}
+}
+
+func TestApplyClickFeedbackPromotesHighCTR(t *testing.T) {
+	results := []Result{
+		{"popular", 0.5},
+		{"middling", 0.3},
+		{"obscure", 0.05},
+	}
+	feedback := []ClickFeedback{
+		{URL: "popular", Clicks: 10, Impressions: 1000},
+		{URL: "obscure", Clicks: 300, Impressions: 500},
+		{URL: "obscure", Clicks: 100, Impressions: 500},
+	}
+	blended := ApplyClickFeedback(results, feedback, 0.5)
+	if blended[0].URL != "obscure" {
+		t.Fatalf("expected the high-CTR page first, got %v", blended)
+	}
+	// 0.5 * 0.05/0.5 + 0.5 * 1
+	if got := blended[0].Rank; math.Abs(got-0.55) > 1e-12 {
+		t.Errorf("obscure blended score = %v, want 0.55", got)
+	}
+	if got := ApplyClickFeedback(results, feedback, 1); got[0].URL != "popular" {
+		t.Errorf("alpha 1 should keep the PageRank order, got %v", got)
+	}
+}
@@ -0,0 +1,119 @@
+package pagerank
+
+import "sort"
+
+// Results adds chainable helpers to a result slice. Each method returns
+// new slices and leaves the receiver alone.
+type Results []Result
+
+// Top returns the k highest-ranked results in the default order. A k
+// larger than the slice returns all of them.
+func (rs Results) Top(k int) Results {
+	if k < 0 {
+		k = 0
+	}
+	sorted := make(Results, len(rs))
+	copy(sorted, rs)
+	sort.Sort(ByRankDesc(sorted))
+	if k < len(sorted) {
+		sorted = sorted[:k]
+	}
+	return sorted
+}
+
+// Filter returns the results for which fn reports true, in order.
+func (rs Results) Filter(fn func(Result) bool) Results {
+	out := make(Results, 0, len(rs))
+	for _, r := range rs {
+		if fn(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Map returns fn applied to each result, in order.
+func (rs Results) Map(fn func(Result) Result) Results {
+	out := make(Results, len(rs))
+	for i, r := range rs {
+		out[i] = fn(r)
+	}
+	return out
+}
+
+// Sum returns the total rank of the results.
+func (rs Results) Sum() float64 {
+	var sum float64
+	for _, r := range rs {
+		sum += r.Rank
+	}
+	return sum
+}
+
+// URLs returns the URL of each result, in order.
+func (rs Results) URLs() []string {
+	urls := make([]string, len(rs))
+	for i, r := range rs {
+		urls[i] = r.URL
+	}
+	return urls
+}
+
+// Ranks returns the rank of each result, in order.
+func (rs Results) Ranks() []float64 {
+	ranks := make([]float64, len(rs))
+	for i, r := range rs {
+		ranks[i] = r.Rank
+	}
+	return ranks
+}
+
+// ResultSet indexes results by URL for constant-time lookup and update.
+// Each URL appears at most once. The zero value is an empty set.
+type ResultSet struct {
+	byURL map[string]*Result
+}
+
+// NewResultSet indexes results; a later duplicate URL replaces an earlier
+// one.
+func NewResultSet(results []Result) *ResultSet {
+	rs := &ResultSet{byURL: make(map[string]*Result, len(results))}
+	for _, r := range results {
+		rs.Set(r)
+	}
+	return rs
+}
+
+func (rs *ResultSet) Get(url string) (Result, bool) {
+	r, ok := rs.byURL[url]
+	if !ok {
+		return Result{}, false
+	}
+	return *r, true
+}
+
+// Set adds result, replacing any existing result for its URL.
+func (rs *ResultSet) Set(result Result) {
+	if r, ok := rs.byURL[result.URL]; ok {
+		r.Rank = result.Rank
+		return
+	}
+	if rs.byURL == nil {
+		rs.byURL = make(map[string]*Result)
+	}
+	rs.byURL[result.URL] = &result
+}
+
+func (rs *ResultSet) Delete(url string) { delete(rs.byURL, url) }
+
+func (rs *ResultSet) Len() int { return len(rs.byURL) }
+
+// ToSlice returns the results in the default order.
+func (rs *ResultSet) ToSlice() []Result {
+	out := make([]Result, 0, len(rs.byURL))
+	for _, r := range rs.byURL {
+		out = append(out, *r)
+	}
+	sort.Sort(ByRankDesc(out))
+	return out
+}
@@ -0,0 +1,109 @@
+package pagerank
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestResultsChain(t *testing.T) {
+	backlinks, outlinks := syntheticGraph(100, 3, 7)
+	results := Results(New().Calculate(backlinks, outlinks))
+
+	if sum := results.Sum(); math.Abs(sum-1) > 1e-6 {
+		t.Errorf("Sum() = %v, want about 1", sum)
+	}
+
+	even := func(r Result) bool {
+		var n int
+		fmt.Sscanf(r.URL[strings.LastIndexByte(r.URL, '-')+1:], "%d", &n)
+		return n%2 == 0
+	}
+	top := results.Filter(even).Top(5)
+	if len(top) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(top))
+	}
+	var want []string
+	for _, r := range results {
+		if even(r) && len(want) < 5 {
+			want = append(want, r.URL)
+		}
+	}
+	if fmt.Sprint(top.URLs()) != fmt.Sprint(want) {
+		t.Errorf("Filter().Top(5).URLs() = %v, want %v", top.URLs(), want)
+	}
+
+	doubled := top.Map(func(r Result) Result { r.Rank *= 2; return r })
+	for i, rank := range doubled.Ranks() {
+		if rank != 2*top[i].Rank {
+			t.Errorf("Map: rank %d = %v, want %v", i, rank, 2*top[i].Rank)
+		}
+	}
+	if got := results.Top(-1); len(got) != 0 {
+		t.Errorf("Top(-1) returned %d results", len(got))
+	}
+	if got := results.Top(1000); len(got) != len(results) {
+		t.Errorf("Top(1000) returned %d results", len(got))
+	}
+}
+
+func TestResultSet(t *testing.T) {
+	results := New().Calculate(syntheticGraph(50, 3, 4))
+	rs := NewResultSet(results)
+	if rs.Len() != len(results) {
+		t.Fatalf("Len = %d, want %d", rs.Len(), len(results))
+	}
+	for _, r := range results {
+		if got, ok := rs.Get(r.URL); !ok || got != r {
+			t.Errorf("Get(%s) = %v, %t", r.URL, got, ok)
+		}
+	}
+
+	top := results[0]
+	rs.Set(Result{URL: top.URL, Rank: 0})
+	rs.Set(Result{URL: "new", Rank: 1})
+	rs.Delete(results[1].URL)
+	rs.Delete("missing")
+	if rs.Len() != len(results) {
+		t.Errorf("Len after overwrite, add and delete = %d, want %d", rs.Len(), len(results))
+	}
+	slice := rs.ToSlice()
+	if slice[0].URL != "new" || slice[len(slice)-1] != (Result{URL: top.URL, Rank: 0}) {
+		t.Errorf("ToSlice not sorted by rank: first %v, last %v", slice[0], slice[len(slice)-1])
+	}
+	seen := make(map[string]bool)
+	for i, r := range slice {
+		if seen[r.URL] {
+			t.Errorf("%s appears twice", r.URL)
+		}
+		seen[r.URL] = true
+		if i > 0 && slice[i-1].Rank < r.Rank {
+			t.Errorf("ToSlice out of order at %d", i)
+		}
+	}
+	if _, ok := rs.Get(results[1].URL); ok {
+		t.Error("deleted URL still present")
+	}
+
+	var zero ResultSet
+	zero.Set(Result{URL: "a", Rank: 0.5})
+	if got, ok := zero.Get("a"); !ok || got.Rank != 0.5 {
+		t.Errorf("zero-value set: Get = %v, %t", got, ok)
+	}
+}
+
+func BenchmarkResultSetGet(b *testing.B) {
+	for _, n := range []int{1_000, 1_000_000} {
+		results := make([]Result, n)
+		for i := range results {
+			results[i] = Result{URL: fmt.Sprintf("page-%d", i), Rank: float64(i)}
+		}
+		rs := NewResultSet(results)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rs.Get(results[i%n].URL)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package pagerank
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WriteTo writes rs as a JSON array of {"URL": ..., "Rank": ...} objects,
+// the same shape the server returns, one result at a time rather than
+// building the whole array in memory.
+func (rs Results) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	write := func(p []byte) error {
+		m, err := w.Write(p)
+		n += int64(m)
+		return err
+	}
+	if err := write([]byte("[")); err != nil {
+		return n, err
+	}
+	for i, r := range rs {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return n, fmt.Errorf("pagerank: encode results: %w", err)
+		}
+		if i > 0 {
+			data = append([]byte(","), data...)
+		}
+		if err := write(data); err != nil {
+			return n, err
+		}
+	}
+	return n, write([]byte("]"))
+}
+
+// ResultDecoder reads a JSON array of results, as written by
+// Results.WriteTo, one element at a time.
+type ResultDecoder struct {
+	dec     *json.Decoder
+	started bool
+	done    bool
+}
+
+func NewResultDecoder(r io.Reader) *ResultDecoder {
+	return &ResultDecoder{dec: json.NewDecoder(r)}
+}
+
+// Decode returns the next result, or io.EOF after the last one.
+func (d *ResultDecoder) Decode() (Result, error) {
+	if d.done {
+		return Result{}, io.EOF
+	}
+	if !d.started {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return Result{}, fmt.Errorf("pagerank: decode results: %w", err)
+		}
+		if tok != json.Delim('[') {
+			return Result{}, fmt.Errorf("pagerank: decode results: expected array, got %v", tok)
+		}
+		d.started = true
+	}
+	if !d.dec.More() {
+		if _, err := d.dec.Token(); err != nil {
+			return Result{}, fmt.Errorf("pagerank: decode results: %w", err)
+		}
+		d.done = true
+		return Result{}, io.EOF
+	}
+	var r Result
+	if err := d.dec.Decode(&r); err != nil {
+		return Result{}, fmt.Errorf("pagerank: decode results: %w", err)
+	}
+	return r, nil
+}
+
+// All decodes the remaining results.
+func (d *ResultDecoder) All() ([]Result, error) {
+	results := []Result{}
+	for {
+		r, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			return results, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+}
+
+// StreamingEncoder writes results as newline-delimited JSON, one object per
+// line, for server-sent events or chunked HTTP responses. Each Encode is
+// flushed to the underlying writer before it returns.
+type StreamingEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewStreamingEncoder returns an encoder that writes to w.
+func NewStreamingEncoder(w io.Writer) *StreamingEncoder {
+	bw := bufio.NewWriter(w)
+	return &StreamingEncoder{w: bw, enc: json.NewEncoder(bw)}
+}
+
+// Encode writes r as one JSON line and flushes it.
+func (e *StreamingEncoder) Encode(r Result) error {
+	if err := e.enc.Encode(r); err != nil {
+		return fmt.Errorf("pagerank: encode result: %w", err)
+	}
+	if err := e.w.Flush(); err != nil {
+		return fmt.Errorf("pagerank: write result: %w", err)
+	}
+	return nil
+}
+
+// Close flushes anything still buffered. It does not close the underlying
+// writer.
+func (e *StreamingEncoder) Close() error {
+	if err := e.w.Flush(); err != nil {
+		return fmt.Errorf("pagerank: write result: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package pagerank
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// marshalResultsJSON encodes results in one call, as a reference for the
+// streaming encoders.
+func marshalResultsJSON(results []Result) ([]byte, error) {
+	if results == nil {
+		results = []Result{}
+	}
+	return json.Marshal(results)
+}
+
+// unmarshalResultsJSON decodes the output of marshalResultsJSON.
+func unmarshalResultsJSON(data []byte) ([]Result, error) {
+	var results []Result
+	err := json.Unmarshal(data, &results)
+	return results, err
+}
+
+func TestResultsWriteToMatchesMarshal(t *testing.T) {
+	results := Results(New().Calculate(sampleGraph()))
+	want, err := marshalResultsJSON(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Results is not an io.Reader, so pair it with one that io.Copy never
+	// reads from: io.Copy prefers the source's WriteTo.
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, struct {
+		io.Reader
+		io.WriterTo
+	}{nil, results})
+	if err != nil {
+		t.Fatalf("io.Copy: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("WriteTo wrote %s, want %s", buf.Bytes(), want)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo reported %d bytes, wrote %d", n, buf.Len())
+	}
+
+	buf.Reset()
+	if _, err := Results(nil).WriteTo(&buf); err != nil || buf.String() != "[]" {
+		t.Errorf("nil results wrote %q, %v", buf.String(), err)
+	}
+}
+
+func TestResultDecoderMatchesUnmarshal(t *testing.T) {
+	results := New().Calculate(syntheticGraph(200, 3, 5))
+	data, err := marshalResultsJSON(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bulk, err := unmarshalResultsJSON(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	d := NewResultDecoder(f)
+	first, err := d.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	rest, err := d.All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	streamed := append([]Result{first}, rest...)
+	if !reflect.DeepEqual(streamed, bulk) {
+		t.Error("streamed results differ from unmarshalResultsJSON")
+	}
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("Decode after the end = %v, want io.EOF", err)
+	}
+}
+
+func TestResultDecoderErrors(t *testing.T) {
+	for _, input := range []string{`{"URL": "a"}`, `[{"URL": "a", "Rank": 0.5},`, `[1]`} {
+		if _, err := NewResultDecoder(strings.NewReader(input)).All(); err == nil {
+			t.Errorf("All(%s) succeeded", input)
+		}
+	}
+	got, err := NewResultDecoder(strings.NewReader("[]")).All()
+	if err != nil || len(got) != 0 {
+		t.Errorf("empty array decoded to %v, %v", got, err)
+	}
+}
+
+func TestStreamingEncoderFlushesEachLine(t *testing.T) {
+	results := New().Calculate(sampleGraph())
+	var buf bytes.Buffer
+	enc := NewStreamingEncoder(&buf)
+	for i, want := range results {
+		if err := enc.Encode(want); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		lines := strings.SplitAfter(buf.String(), "\n")
+		if len(lines) != i+2 || lines[i+1] != "" {
+			t.Fatalf("after %d Encode calls the writer holds %q", i+1, buf.String())
+		}
+		var got Result
+		if err := json.Unmarshal([]byte(lines[i]), &got); err != nil {
+			t.Fatalf("line %d %q: %v", i, lines[i], err)
+		}
+		if got != want {
+			t.Errorf("line %d decoded to %v, want %v", i, got, want)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestStreamingEncoderWrapsWriteErrors(t *testing.T) {
+	err := NewStreamingEncoder(failingWriter{}).Encode(Result{URL: "a", Rank: 1})
+	if err == nil || !strings.HasPrefix(err.Error(), "pagerank: ") {
+		t.Errorf("expected a pagerank write error, got %v", err)
+	}
+}
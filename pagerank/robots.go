@@ -0,0 +1,164 @@
+package pagerank
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+type robotsRule struct {
+	path  string
+	allow bool
+}
+
+// robotsEntry holds one host's rules. Each host is fetched under its own
+// once so a slow robots.txt does not stall lookups for other hosts.
+type robotsEntry struct {
+	once  sync.Once
+	rules []robotsRule
+}
+
+// RobotsFilter decides whether links may be followed according to the
+// target host's robots.txt. Rules are fetched once per host and cached.
+type RobotsFilter struct {
+	userAgent   string
+	fetchRobots func(host string) (io.ReadCloser, error)
+
+	mu    sync.Mutex
+	hosts map[string]*robotsEntry
+}
+
+// NewRobotsFilter returns a filter for userAgent. fetchRobots supplies the
+// robots.txt body for a host; if it fails, every path on that host is
+// allowed, matching how crawlers treat a missing robots.txt.
+func NewRobotsFilter(userAgent string, fetchRobots func(host string) (io.ReadCloser, error)) *RobotsFilter {
+	return &RobotsFilter{
+		userAgent:   strings.ToLower(userAgent),
+		fetchRobots: fetchRobots,
+		hosts:       make(map[string]*robotsEntry),
+	}
+}
+
+// Allow reports whether the link from source to target may be followed.
+// Targets that cannot be parsed as URLs are rejected.
+func (rf *RobotsFilter) Allow(source, target string) bool {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	best, allowed := -1, true
+	for _, rule := range rf.hostRules(u.Host) {
+		if len(rule.path) < best || !robotsMatch(rule.path, path) {
+			continue
+		}
+		if len(rule.path) > best || rule.allow {
+			best, allowed = len(rule.path), rule.allow
+		}
+	}
+	return allowed
+}
+
+func (rf *RobotsFilter) hostRules(host string) []robotsRule {
+	rf.mu.Lock()
+	entry, ok := rf.hosts[host]
+	if !ok {
+		entry = &robotsEntry{}
+		rf.hosts[host] = entry
+	}
+	rf.mu.Unlock()
+
+	entry.once.Do(func() {
+		if body, err := rf.fetchRobots(host); err == nil {
+			entry.rules = parseRobots(body, rf.userAgent)
+			body.Close()
+		}
+	})
+	return entry.rules
+}
+
+// parseRobots returns the rules of the group that best matches userAgent,
+// falling back to the "*" group.
+func parseRobots(r io.Reader, userAgent string) []robotsRule {
+	var specific, wildcard []robotsRule
+	var agents []string
+	inRules := false
+	matchedSpecific := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue
+			}
+			rule := robotsRule{path: value, allow: key == "allow"}
+			for _, agent := range agents {
+				switch {
+				case agent == "*":
+					wildcard = append(wildcard, rule)
+				case userAgent != "" && strings.Contains(userAgent, agent):
+					specific = append(specific, rule)
+					matchedSpecific = true
+				}
+			}
+		}
+	}
+	if matchedSpecific {
+		return specific
+	}
+	return wildcard
+}
+
+// robotsMatch matches a robots.txt path pattern, supporting the "*"
+// wildcard and a trailing "$" anchor.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	for _, part := range parts[1:] {
+		i := strings.Index(path[pos:], part)
+		if i < 0 {
+			return false
+		}
+		pos += i + len(part)
+	}
+	if !anchored {
+		return true
+	}
+	if len(parts) > 1 {
+		return strings.HasSuffix(path, parts[len(parts)-1])
+	}
+	return pos == len(path)
+}
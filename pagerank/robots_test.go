@@ -0,0 +1,99 @@
+package pagerank
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+const sampleRobots = `# example robots.txt
+User-agent: *
+Disallow: /private/
+Allow: /private/press/
+Disallow: /*.pdf$
+
+User-agent: gigglebot
+Disallow: /drafts/
+`
+
+func mockRobots(fetches *int) func(string) (io.ReadCloser, error) {
+	return func(host string) (io.ReadCloser, error) {
+		*fetches++
+		if host != "example.com" {
+			return nil, errors.New("not found")
+		}
+		return io.NopCloser(strings.NewReader(sampleRobots)), nil
+	}
+}
+
+func TestRobotsFilterAllow(t *testing.T) {
+	var fetches int
+	rf := NewRobotsFilter("OtherBot/1.0", mockRobots(&fetches))
+	cases := map[string]bool{
+		"https://example.com/":                     true,
+		"https://example.com/public/page":          true,
+		"https://example.com/private/secret":       false,
+		"https://example.com/private/press/today":  true,
+		"https://example.com/files/report.pdf":     false,
+		"https://example.com/files/report.pdf?v=2": true,
+		"https://example.com/drafts/post":          true,
+		"https://unknown.example.org/private/x":    true,
+	}
+	for target, want := range cases {
+		if got := rf.Allow("https://example.com/", target); got != want {
+			t.Errorf("Allow(%s) = %v, want %v", target, got, want)
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("expected robots.txt to be fetched once per host (2), got %d", fetches)
+	}
+}
+
+func TestRobotsFilterUserAgentGroup(t *testing.T) {
+	var fetches int
+	rf := NewRobotsFilter("GiggleBot/2.1", mockRobots(&fetches))
+	if rf.Allow("", "https://example.com/drafts/post") {
+		t.Error("gigglebot group should disallow /drafts/")
+	}
+	if !rf.Allow("", "https://example.com/private/secret") {
+		t.Error("a matching agent group replaces the * group")
+	}
+}
+
+func TestRobotsFilterSlowHostDoesNotBlockOthers(t *testing.T) {
+	release := make(chan struct{})
+	rf := NewRobotsFilter("GiggleBot", func(host string) (io.ReadCloser, error) {
+		if host == "slow.example.com" {
+			<-release
+		}
+		return io.NopCloser(strings.NewReader(sampleRobots)), nil
+	})
+
+	done := make(chan bool)
+	go func() { done <- rf.Allow("", "https://slow.example.com/drafts/post") }()
+
+	if rf.Allow("", "https://example.com/drafts/post") {
+		t.Error("gigglebot group should disallow /drafts/")
+	}
+	close(release)
+	if <-done {
+		t.Error("slow host should apply its rules once fetched")
+	}
+}
+
+func TestGraphWithRobotsFilter(t *testing.T) {
+	var fetches int
+	g := NewGraph().WithRobotsFilter(NewRobotsFilter("OtherBot", mockRobots(&fetches)))
+	g.AddEdge("https://example.com/", "https://example.com/about").
+		AddEdge("https://example.com/", "https://example.com/private/admin").
+		AddEdge("https://example.com/about", "https://example.com/private/keys")
+
+	if g.EdgeCount() != 1 {
+		t.Errorf("expected disallowed edges to be skipped, got %d edges", g.EdgeCount())
+	}
+	backlinks, _ := g.Build()
+	if _, ok := backlinks["https://example.com/private/admin"]; ok {
+		t.Error("disallowed target should not appear in backlinks")
+	}
+}
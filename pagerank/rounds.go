@@ -0,0 +1,20 @@
+package pagerank
+
+// RunMultiRound ranks graph rounds times. After every round but the last,
+// postRound sees that round's results (rounds are numbered from 1) and
+// returns the graph for the next round; a nil graph keeps the current one.
+// It returns the final round's results.
+func RunMultiRound(rounds int, graph *Graph, calc *Calculator, postRound func(round int, results []Result) *Graph) []Result {
+	results := []Result{}
+	for round := 1; round <= rounds; round++ {
+		backlinks, outlinks := graph.Build()
+		results = calc.Calculate(backlinks, outlinks)
+		if round == rounds || postRound == nil {
+			continue
+		}
+		if next := postRound(round, results); next != nil {
+			graph = next
+		}
+	}
+	return results
+}
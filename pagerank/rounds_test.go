@@ -0,0 +1,59 @@
+package pagerank
+
+import (
+	"fmt"
+	"testing"
+)
+
+// concentration is the sum of squared ranks: 1/n for a uniform
+// distribution, 1 when one page holds all the rank.
+func concentration(results []Result) float64 {
+	var sum float64
+	for _, r := range results {
+		sum += r.Rank * r.Rank
+	}
+	return sum
+}
+
+func TestRunMultiRoundBoostConcentrates(t *testing.T) {
+	backlinks, _ := GeneratePowerLawGraph(200, 4, 3)
+	base := NewGraph()
+	for target, sources := range backlinks {
+		for _, src := range sources {
+			base.AddEdge(src, target)
+		}
+	}
+	calc := New()
+	first := RunMultiRound(1, base, calc, nil)
+
+	// The boost rebuilds the graph with an extra link from every page to
+	// the previous round's ten highest-ranked pages.
+	var calls []int
+	boosted := RunMultiRound(2, base, calc, func(round int, results []Result) *Graph {
+		calls = append(calls, round)
+		g := NewGraph()
+		for target, sources := range backlinks {
+			for _, src := range sources {
+				g.AddEdge(src, target)
+			}
+		}
+		for _, r := range results {
+			for _, top := range results[:10] {
+				if r.URL != top.URL {
+					g.AddEdge(r.URL, top.URL)
+				}
+			}
+		}
+		return g
+	})
+
+	if fmt.Sprint(calls) != "[1]" {
+		t.Errorf("postRound called for rounds %v, want [1]", calls)
+	}
+	if concentration(boosted) <= concentration(first) {
+		t.Errorf("boosting did not concentrate rank: %v -> %v", concentration(first), concentration(boosted))
+	}
+	if got := RunMultiRound(0, base, calc, nil); len(got) != 0 {
+		t.Errorf("zero rounds returned %d results", len(got))
+	}
+}
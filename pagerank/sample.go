@@ -0,0 +1,52 @@
+package pagerank
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// SampleGraph picks fraction of the ranked pages, drawing the same share
+// from every power-of-ten rank bucket so both hubs and the long tail are
+// represented, and returns the subgraph induced by the picked pages.
+// Every non-empty bucket contributes at least one page, so a bucket too
+// small for its quota to round up, such as a lone hub, is not dropped.
+// Pages in backlinks that have no result are never picked.
+func SampleGraph(backlinks map[string][]string, results []Result, fraction float64, seed int64) (map[string][]string, map[string]int) {
+	if fraction <= 0 || len(results) == 0 {
+		return map[string][]string{}, map[string]int{}
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+
+	buckets := make(map[int][]string)
+	for _, r := range results {
+		b := math.MinInt32
+		if r.Rank > 0 {
+			b = int(math.Floor(math.Log10(r.Rank)))
+		}
+		buckets[b] = append(buckets[b], r.URL)
+	}
+	keys := make([]int, 0, len(buckets))
+	for b := range buckets {
+		keys = append(keys, b)
+	}
+	sort.Ints(keys)
+
+	// Quotas are taken against the running total so rounding errors do not
+	// pile up across buckets.
+	rng := rand.New(rand.NewSource(seed))
+	var picked []string
+	seen := 0
+	for _, b := range keys {
+		urls := buckets[b]
+		sort.Strings(urls)
+		rng.Shuffle(len(urls), func(i, j int) { urls[i], urls[j] = urls[j], urls[i] })
+		want := int(math.Round(fraction*float64(seen+len(urls)))) - len(picked)
+		seen += len(urls)
+		want = min(max(want, 1), len(urls))
+		picked = append(picked, urls[:want]...)
+	}
+	return inducedSubgraph(backlinks, picked)
+}
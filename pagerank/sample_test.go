@@ -0,0 +1,56 @@
+package pagerank
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestSampleGraphStratifiedByRank(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(2000, 5, 1)
+	results := New().Calculate(backlinks, outlinks)
+
+	sub, subOutlinks := SampleGraph(backlinks, results, 0.1, 42)
+	want := 0.1 * float64(len(results))
+	if got := float64(len(subOutlinks)); math.Abs(got-want) > want*0.1 {
+		t.Fatalf("expected about %.0f sampled nodes, got %.0f", want, got)
+	}
+
+	rank := make(map[string]float64, len(results))
+	for _, r := range results {
+		rank[r.URL] = r.Rank
+	}
+	decades := make(map[int]bool)
+	for url := range subOutlinks {
+		decades[int(math.Floor(math.Log10(rank[url])))] = true
+	}
+	if len(decades) < 2 {
+		t.Errorf("expected the sample to span several rank buckets, got %v", decades)
+	}
+
+	for target, sources := range sub {
+		if _, ok := subOutlinks[target]; !ok {
+			t.Fatalf("edge target %s is not in the sample", target)
+		}
+		for _, src := range sources {
+			if _, ok := subOutlinks[src]; !ok {
+				t.Fatalf("edge source %s is not in the sample", src)
+			}
+		}
+	}
+}
+
+func TestSampleGraphKeepsLoneHubBucket(t *testing.T) {
+	backlinks := map[string][]string{}
+	results := []Result{{URL: "hub", Rank: 0.5}}
+	for i := 0; i < 20; i++ {
+		url := fmt.Sprintf("tail-%02d", i)
+		backlinks["hub"] = append(backlinks["hub"], url)
+		results = append(results, Result{URL: url, Rank: 0.025})
+	}
+
+	_, subOutlinks := SampleGraph(backlinks, results, 0.1, 1)
+	if _, ok := subOutlinks["hub"]; !ok {
+		t.Errorf("expected the single-page hub bucket to be sampled, got %v", subOutlinks)
+	}
+}
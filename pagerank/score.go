@@ -0,0 +1,43 @@
+package pagerank
+
+import "sort"
+
+// ScoreMap indexes results by URL.
+func ScoreMap(results []Result) map[string]float64 {
+	m := make(map[string]float64, len(results))
+	for _, r := range results {
+		m[r.URL] = r.Rank
+	}
+	return m
+}
+
+// ScorePage returns url's rank from results. A page that is not in results
+// is estimated with a single update step: its teleport share of a graph
+// that includes it, plus the damped rank each backlink already has in
+// results passes along. Backlinks missing from results contribute nothing.
+func (c *Calculator) ScorePage(url string, backlinks map[string][]string, outlinksCount map[string]int, results []Result) float64 {
+	for _, r := range results {
+		if r.URL == url {
+			return r.Rank
+		}
+	}
+
+	urls := collectURLs(backlinks, outlinksCount)
+	if i := sort.SearchStrings(urls, url); i == len(urls) || urls[i] != url {
+		urls = append(urls, "")
+		copy(urls[i+1:], urls[i:])
+		urls[i] = url
+	}
+	tele := c.teleportVector(urls)[sort.SearchStrings(urls, url)]
+
+	ranks := ScoreMap(results)
+	var contrib float64
+	for _, src := range backlinks[url] {
+		out := outlinksCount[src]
+		if out <= 0 {
+			out = 1
+		}
+		contrib += ranks[src] / float64(out)
+	}
+	return tele + c.damping*contrib
+}
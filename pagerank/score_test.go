@@ -0,0 +1,49 @@
+package pagerank
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScoreMap(t *testing.T) {
+	results := []Result{{URL: "a", Rank: 0.6}, {URL: "b", Rank: 0.4}}
+	m := ScoreMap(results)
+	if len(m) != 2 || m["a"] != 0.6 || m["b"] != 0.4 {
+		t.Errorf("unexpected map %v", m)
+	}
+}
+
+func TestScorePage(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	calc := New()
+	results := calc.Calculate(backlinks, outlinks)
+	for _, r := range results {
+		if got := calc.ScorePage(r.URL, backlinks, outlinks, results); got != r.Rank {
+			t.Errorf("ScorePage(%s) = %v, want stored %v", r.URL, got, r.Rank)
+		}
+	}
+
+	// In a large graph one new page barely moves the others, so one step
+	// from the old ranks should land close to its converged rank.
+	backlinks, outlinks = GeneratePowerLawGraph(500, 5, 1)
+	results = calc.Calculate(backlinks, outlinks)
+	sources := []string{results[200].URL, results[300].URL, results[400].URL}
+	backlinks["page-new"] = sources
+	last := results[len(results)-1].URL
+	backlinks[last] = append(backlinks[last], "page-new")
+	for _, src := range sources {
+		outlinks[src]++
+	}
+	outlinks["page-new"] = 1
+
+	var want float64
+	for _, r := range calc.Calculate(backlinks, outlinks) {
+		if r.URL == "page-new" {
+			want = r.Rank
+		}
+	}
+	got := calc.ScorePage("page-new", backlinks, outlinks, results)
+	if want == 0 || math.Abs(got-want) > 0.1*want {
+		t.Errorf("ScorePage estimate %v too far from converged %v", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package pagerank
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiWhite = "\x1b[37m"
+	ansiReset = "\x1b[0m"
+)
+
+// SimulatePageRankSteps is WritePageRankSteps without color or the error,
+// for writers that cannot fail, such as a bytes.Buffer.
+func SimulatePageRankSteps(backlinks map[string][]string, outlinksCount map[string]int, iterations int, w io.Writer) {
+	_ = WritePageRankSteps(backlinks, outlinksCount, iterations, w, false)
+}
+
+// WritePageRankSteps runs the default calculator for iterations steps and
+// writes the rank of every page after each one, together with how much it
+// moved since the previous step. With color set, increases are shown in red
+// and decreases in green. It stops writing at the first error and returns it.
+func WritePageRankSteps(backlinks map[string][]string, outlinksCount map[string]int, iterations int, w io.Writer, color bool) error {
+	var prev []float64
+	var werr error
+
+	calc := New().SetIterations(iterations)
+	calc.observe = func(iteration int, urls []string, rank []float64) {
+		if werr != nil {
+			return
+		}
+		if prev == nil {
+			prev = make([]float64, len(rank))
+			for i := range prev {
+				prev[i] = 1.0 / float64(len(rank))
+			}
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "Iteration %d\n", iteration)
+		fmt.Fprintf(&b, "%-30s | %-10s | %s\n", "URL", "Rank", "Delta")
+		fmt.Fprintln(&b, "-------------------------------+------------+------------")
+		for i, url := range urls {
+			delta := fmt.Sprintf("%+.6f", rank[i]-prev[i])
+			if color && rank[i] > prev[i] {
+				delta = ansiRed + delta + ansiReset
+			} else if color && rank[i] < prev[i] {
+				delta = ansiGreen + delta + ansiReset
+			}
+			fmt.Fprintf(&b, "%-30s | %.8f | %s\n", url, rank[i], delta)
+		}
+		fmt.Fprintln(&b)
+		copy(prev, rank)
+		_, werr = io.WriteString(w, b.String())
+	}
+	calc.Calculate(backlinks, outlinksCount)
+	return werr
+}
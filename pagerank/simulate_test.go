@@ -0,0 +1,51 @@
+package pagerank
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSimulatePageRankSteps(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	var buf bytes.Buffer
+	SimulatePageRankSteps(backlinks, outlinks, 3, &buf)
+
+	out := buf.String()
+	tables := strings.Split(strings.TrimSpace(out), "\n\n")
+	if len(tables) != 3 {
+		t.Fatalf("expected 3 iteration tables, got %d:\n%s", len(tables), out)
+	}
+	for i, table := range tables {
+		if !strings.HasPrefix(table, "Iteration "+string(rune('1'+i))) {
+			t.Errorf("table %d has wrong header: %q", i, strings.SplitN(table, "\n", 2)[0])
+		}
+		for _, url := range []string{"page-a", "page-b", "page-c", "page-d"} {
+			if !strings.Contains(table, url+" ") {
+				t.Errorf("table %d missing %s", i, url)
+			}
+		}
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Error("color output should be off by default")
+	}
+}
+
+func TestWritePageRankStepsColor(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	var buf bytes.Buffer
+	if err := WritePageRankSteps(backlinks, outlinks, 1, &buf, true); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, ansiRed) || !strings.Contains(out, ansiGreen) {
+		t.Errorf("expected both increases and decreases to be colored:\n%q", out)
+	}
+}
+
+func TestWritePageRankStepsReturnsWriteError(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	if err := WritePageRankSteps(backlinks, outlinks, 3, failingWriter{}, false); err == nil {
+		t.Error("expected the write error to be returned")
+	}
+}
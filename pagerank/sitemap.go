@@ -0,0 +1,83 @@
+package pagerank
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// SiteMap totals rank over the URL path hierarchy. Hosts are ignored, so
+// results from several sites merge by path. The zero value is empty; use
+// Build to fill it.
+type SiteMap struct {
+	total   map[string]float64
+	byDepth [][]string
+}
+
+// Build replaces the map's contents with the totals for results and
+// returns sm. Every result adds its rank to "/" and to each ancestor path
+// down to maxDepth components; deeper pages count towards their ancestor
+// at maxDepth. A negative maxDepth is treated as 0.
+func (sm *SiteMap) Build(results []Result, maxDepth int) *SiteMap {
+	if maxDepth < 0 {
+		maxDepth = 0
+	}
+	sm.total = make(map[string]float64)
+	sm.byDepth = make([][]string, maxDepth+1)
+	add := func(path string, depth int, rank float64) {
+		if _, ok := sm.total[path]; !ok {
+			sm.byDepth[depth] = append(sm.byDepth[depth], path)
+		}
+		sm.total[path] += rank
+	}
+	for _, r := range results {
+		parts := pathComponents(r.URL)
+		if len(parts) > maxDepth {
+			parts = parts[:maxDepth]
+		}
+		add("/", 0, r.Rank)
+		for d := range parts {
+			add("/"+strings.Join(parts[:d+1], "/"), d+1, r.Rank)
+		}
+	}
+	return sm
+}
+
+// TotalRank is the summed rank of path and everything below it, or 0 if
+// no result fell under it. Trailing slashes are ignored.
+func (sm *SiteMap) TotalRank(path string) float64 {
+	return sm.total["/"+strings.Join(pathComponents(path), "/")]
+}
+
+// TopPaths returns the k paths at depth with the highest totals, as
+// results whose URL is the path. Depth 0 is "/" alone.
+func (sm *SiteMap) TopPaths(depth, k int) []Result {
+	if depth < 0 || depth >= len(sm.byDepth) || k <= 0 {
+		return []Result{}
+	}
+	out := make([]Result, 0, len(sm.byDepth[depth]))
+	for _, path := range sm.byDepth[depth] {
+		out = append(out, Result{URL: path, Rank: sm.total[path]})
+	}
+	sort.Sort(ByRankDesc(out))
+	if k < len(out) {
+		out = out[:k]
+	}
+	return out
+}
+
+// pathComponents splits the path of rawURL, or rawURL itself if it is
+// just a path, into its non-empty segments.
+func pathComponents(rawURL string) []string {
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+	var parts []string
+	for _, p := range strings.Split(path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
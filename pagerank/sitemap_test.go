@@ -0,0 +1,50 @@
+package pagerank
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSiteMapAggregates(t *testing.T) {
+	results := []Result{
+		{"https://example.com/", 0.1},
+		{"https://example.com/blog/", 0.05},
+		{"https://example.com/blog/2024/go-generics", 0.2},
+		{"https://example.com/blog/2024/fuzzing", 0.1},
+		{"https://example.com/blog/2023/modules", 0.05},
+		{"https://example.com/docs/install", 0.35},
+		{"https://example.com/docs/api/v2/search", 0.2},
+	}
+	sm := new(SiteMap).Build(results, 2)
+
+	var sum float64
+	for _, r := range results {
+		sum += r.Rank
+	}
+	cases := map[string]float64{
+		"/":           sum,
+		"/blog":       0.4,
+		"/blog/2024/": 0.3,
+		"/docs":       0.55,
+		// Deeper than maxDepth, so folded into /docs/api.
+		"/docs/api":    0.2,
+		"/docs/api/v2": 0,
+		"/missing":     0,
+	}
+	for path, want := range cases {
+		if got := sm.TotalRank(path); math.Abs(got-want) > 1e-12 {
+			t.Errorf("TotalRank(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	top := sm.TopPaths(1, 1)
+	if len(top) != 1 || top[0].URL != "/docs" || math.Abs(top[0].Rank-0.55) > 1e-12 {
+		t.Errorf("TopPaths(1, 1) = %v, want /docs with 0.55", top)
+	}
+	if got := sm.TopPaths(2, 10); len(got) != 4 || got[0].URL != "/docs/install" {
+		t.Errorf("TopPaths(2, 10) = %v", got)
+	}
+	if got := sm.TopPaths(3, 10); len(got) != 0 {
+		t.Errorf("depth beyond maxDepth returned %v", got)
+	}
+}
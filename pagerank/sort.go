@@ -0,0 +1,48 @@
+package pagerank
+
+import "sort"
+
+// Less reports whether r sorts before other in the default result order:
+// rank descending, then URL ascending.
+func (r Result) Less(other Result) bool {
+	if r.Rank == other.Rank {
+		return r.URL < other.URL
+	}
+	return r.Rank > other.Rank
+}
+
+type byRankDesc []Result
+
+func (s byRankDesc) Len() int           { return len(s) }
+func (s byRankDesc) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byRankDesc) Less(i, j int) bool { return s[i].Less(s[j]) }
+
+type byRankAsc []Result
+
+func (s byRankAsc) Len() int      { return len(s) }
+func (s byRankAsc) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byRankAsc) Less(i, j int) bool {
+	if s[i].Rank == s[j].Rank {
+		return s[i].URL < s[j].URL
+	}
+	return s[i].Rank < s[j].Rank
+}
+
+type byURLAsc []Result
+
+func (s byURLAsc) Len() int           { return len(s) }
+func (s byURLAsc) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byURLAsc) Less(i, j int) bool { return s[i].URL < s[j].URL }
+
+func ByRankDesc(results []Result) sort.Interface { return byRankDesc(results) }
+func ByRankAsc(results []Result) sort.Interface  { return byRankAsc(results) }
+func ByURLAsc(results []Result) sort.Interface   { return byURLAsc(results) }
+
+// StableSort returns a sorted copy of results; elements that compare equal
+// keep their original relative order.
+func StableSort(results []Result, less func(a, b Result) bool) []Result {
+	out := make([]Result, len(results))
+	copy(out, results)
+	sort.SliceStable(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
@@ -0,0 +1,64 @@
+package pagerank
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestResultLess(t *testing.T) {
+	a := Result{URL: "page-a", Rank: 0.5}
+	b := Result{URL: "page-b", Rank: 0.5}
+	c := Result{URL: "page-c", Rank: 0.9}
+	if !c.Less(a) || a.Less(c) {
+		t.Error("higher rank should sort first")
+	}
+	if !a.Less(b) || b.Less(a) {
+		t.Error("equal ranks should tie-break by URL ascending")
+	}
+}
+
+func TestSortAdapters(t *testing.T) {
+	base := []Result{
+		{URL: "page-b", Rank: 0.2},
+		{URL: "page-c", Rank: 0.5},
+		{URL: "page-a", Rank: 0.3},
+	}
+	cases := []struct {
+		name string
+		sort func([]Result) sort.Interface
+		want []string
+	}{
+		{"ByRankDesc", ByRankDesc, []string{"page-c", "page-a", "page-b"}},
+		{"ByRankAsc", ByRankAsc, []string{"page-b", "page-a", "page-c"}},
+		{"ByURLAsc", ByURLAsc, []string{"page-a", "page-b", "page-c"}},
+	}
+	for _, tc := range cases {
+		rs := append([]Result(nil), base...)
+		sort.Sort(tc.sort(rs))
+		for i, want := range tc.want {
+			if rs[i].URL != want {
+				t.Errorf("%s: position %d = %s, want %s", tc.name, i, rs[i].URL, want)
+			}
+		}
+	}
+}
+
+func TestStableSortPreservesInsertionOrder(t *testing.T) {
+	in := []Result{
+		{URL: "page-z", Rank: 0.1},
+		{URL: "page-m", Rank: 0.4},
+		{URL: "page-a", Rank: 0.1},
+		{URL: "page-q", Rank: 0.1},
+	}
+	out := StableSort(in, func(a, b Result) bool { return a.Rank > b.Rank })
+
+	want := []string{"page-m", "page-z", "page-a", "page-q"}
+	for i, w := range want {
+		if out[i].URL != w {
+			t.Fatalf("position %d = %s, want %s", i, out[i].URL, w)
+		}
+	}
+	if in[0].URL != "page-z" || in[1].URL != "page-m" {
+		t.Error("StableSort must not modify its input")
+	}
+}
@@ -0,0 +1,77 @@
+package pagerank
+
+import (
+	"math"
+	"sort"
+)
+
+// ComputeSpamScore flags likely link farms: pages whose outlink count is
+// above the percentileThreshold-th percentile (0-100) of the counts of
+// pages that link anywhere. A flagged page with out outlinks against a
+// threshold t scores (out-t)/out, so under SetSpamScores it passes on
+// only t/out of its usual rank. Pages at or below the threshold are left
+// out of the map, as is everything when the threshold is outside [0, 100].
+func ComputeSpamScore(outlinksCount map[string]int, percentileThreshold float64) map[string]float64 {
+	scores := make(map[string]float64)
+	if percentileThreshold < 0 || percentileThreshold > 100 {
+		return scores
+	}
+	counts := make([]int, 0, len(outlinksCount))
+	for _, n := range outlinksCount {
+		if n > 0 {
+			counts = append(counts, n)
+		}
+	}
+	if len(counts) == 0 {
+		return scores
+	}
+	sort.Ints(counts)
+	// Nearest-rank percentile.
+	k := int(math.Ceil(percentileThreshold / 100 * float64(len(counts))))
+	if k < 1 {
+		k = 1
+	}
+	threshold := float64(counts[k-1])
+	for url, n := range outlinksCount {
+		if out := float64(n); out > threshold {
+			scores[url] = (out - threshold) / out
+		}
+	}
+	return scores
+}
+
+// applySpamScores scales down everything a flagged page passes on by
+// 1 - its score, as if its damping factor were multiplied by that much.
+func (c *Calculator) applySpamScores(g *linkIndex) {
+	keep := make(map[string]float64, len(c.spam))
+	for url, s := range c.spam {
+		keep[url] = 1 - s
+	}
+	scaleOutflow(g, keep)
+}
+
+// scaleOutflow multiplies the share of rank every link out of url carries
+// by keep[url].
+func scaleOutflow(g *linkIndex, keep map[string]float64) {
+	for url, k := range keep {
+		i, ok := g.index[url]
+		if !ok {
+			continue
+		}
+		if k <= 0 {
+			g.out[i] = math.Inf(1)
+		} else {
+			g.out[i] /= k
+		}
+	}
+	if g.weights == nil {
+		return
+	}
+	for i, sources := range g.sources {
+		for k, src := range sources {
+			if f, ok := keep[g.urls[src]]; ok {
+				g.weights[i][k] *= f
+			}
+		}
+	}
+}
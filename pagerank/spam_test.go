@@ -0,0 +1,81 @@
+package pagerank
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestComputeSpamScore(t *testing.T) {
+	outlinks := map[string]int{"sink": 0, "farm": 40}
+	for i := 0; i < 9; i++ {
+		outlinks[fmt.Sprintf("p%d", i)] = 10
+	}
+	scores := ComputeSpamScore(outlinks, 90)
+	if len(scores) != 1 {
+		t.Fatalf("expected only the farm to be flagged, got %v", scores)
+	}
+	if got := scores["farm"]; got != 0.75 {
+		t.Errorf("farm score = %v, want 0.75", got)
+	}
+	if got := ComputeSpamScore(outlinks, 101); len(got) != 0 {
+		t.Errorf("out-of-range threshold flagged %v", got)
+	}
+}
+
+// passedRank sums how far each page linked from src rises above the
+// teleport baseline. Every target is linked from src alone.
+func passedRank(results []Result, src string, n int) float64 {
+	ranks := ScoreMap(results)
+	base := (1 - New().Damping()) / float64(len(results))
+	var sum float64
+	for i := 0; i < n; i++ {
+		sum += ranks[fmt.Sprintf("%s-t%d", src, i)] - base
+	}
+	return sum
+}
+
+func TestSpamScoresPenalizeLinkFarm(t *testing.T) {
+	backlinks := make(map[string][]string)
+	outlinks := make(map[string]int)
+	link := func(src string, n int) {
+		outlinks[src] = n
+		for i := 0; i < n; i++ {
+			target := fmt.Sprintf("%s-t%d", src, i)
+			backlinks[target] = []string{src}
+			outlinks[target] = 0
+		}
+	}
+	link("farm", 1000)
+	link("page", 10)
+	for i := 0; i < 20; i++ {
+		link(fmt.Sprintf("filler%d", i), 10)
+	}
+
+	plain := New().Calculate(backlinks, outlinks)
+	farm, page := passedRank(plain, "farm", 1000), passedRank(plain, "page", 10)
+	if d := farm - page; d > 1e-12 || d < -1e-12 {
+		t.Fatalf("without scores both pages should pass the same rank, got %g and %g", farm, page)
+	}
+
+	scores := ComputeSpamScore(outlinks, 90)
+	calc := New().SetSpamScores(scores)
+	for _, normalize := range []bool{false, true} {
+		if normalize {
+			calc.SetOutlinkNormalizer(func(_ string, targets []string, _ map[string]int) map[string]float64 {
+				w := make(map[string]float64, len(targets))
+				for _, t := range targets {
+					w[t] = 1
+				}
+				return w
+			})
+		}
+		results := calc.Calculate(backlinks, outlinks)
+		farm, page := passedRank(results, "farm", 1000), passedRank(results, "page", 10)
+		if farm >= page {
+			t.Errorf("normalizer %t: farm passed %g, page passed %g", normalize, farm, page)
+		}
+		if want := page * (1 - scores["farm"]); farm > want*1.0001 || farm < want*0.9999 {
+			t.Errorf("normalizer %t: farm passed %g, want %g", normalize, farm, want)
+		}
+	}
+}
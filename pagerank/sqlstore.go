@@ -0,0 +1,80 @@
+package pagerank
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SaveResultsToDB writes results under runID into tableName, creating the
+// table if needed. All rows are inserted in a single transaction.
+func SaveResultsToDB(db *sql.DB, results []Result, tableName string, runID string) error {
+	if !tableNamePattern.MatchString(tableName) {
+		return fmt.Errorf("pagerank: invalid table name %q", tableName)
+	}
+
+	create := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		run_id TEXT,
+		url TEXT,
+		rank REAL,
+		rank_at TIMESTAMP
+	)`, tableName)
+	if _, err := db.Exec(create); err != nil {
+		return fmt.Errorf("pagerank: create table %s: %w", tableName, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("pagerank: begin transaction: %w", err)
+	}
+	stmt, err := tx.Prepare(fmt.Sprintf(
+		"INSERT INTO %s (run_id, url, rank, rank_at) VALUES (?, ?, ?, ?)", tableName))
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("pagerank: prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	for _, r := range results {
+		if _, err := stmt.Exec(runID, r.URL, r.Rank, now); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("pagerank: insert %s: %w", r.URL, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("pagerank: commit: %w", err)
+	}
+	return nil
+}
+
+// LoadResultsFromDB reads the results stored under runID, sorted by rank
+// descending.
+func LoadResultsFromDB(db *sql.DB, tableName string, runID string) ([]Result, error) {
+	if !tableNamePattern.MatchString(tableName) {
+		return nil, fmt.Errorf("pagerank: invalid table name %q", tableName)
+	}
+
+	rows, err := db.Query(fmt.Sprintf(
+		"SELECT url, rank FROM %s WHERE run_id = ? ORDER BY rank DESC, url ASC", tableName), runID)
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: query %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	out := []Result{}
+	for rows.Next() {
+		var r Result
+		if err := rows.Scan(&r.URL, &r.Rank); err != nil {
+			return nil, fmt.Errorf("pagerank: scan row: %w", err)
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("pagerank: read rows: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,58 @@
+package pagerank
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSaveAndLoadResultsRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+
+	results := make([]Result, 100)
+	for i := range results {
+		results[i] = Result{URL: fmt.Sprintf("page-%03d", i), Rank: float64(100-i) / 5050}
+	}
+	if err := SaveResultsToDB(db, results, "pagerank_scores", "run-1"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := SaveResultsToDB(db, results[:10], "pagerank_scores", "run-2"); err != nil {
+		t.Fatalf("save second run: %v", err)
+	}
+
+	got, err := LoadResultsFromDB(db, "pagerank_scores", "run-1")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got) != len(results) {
+		t.Fatalf("expected %d results, got %d", len(results), len(got))
+	}
+	for i := range results {
+		if got[i] != results[i] {
+			t.Errorf("result %d = %v, want %v", i, got[i], results[i])
+		}
+	}
+}
+
+func TestSaveResultsRejectsInvalidTableName(t *testing.T) {
+	db := openTestDB(t)
+	if err := SaveResultsToDB(db, nil, "scores; DROP TABLE x", "run"); err == nil {
+		t.Error("expected error for invalid table name")
+	}
+	if _, err := LoadResultsFromDB(db, "bad name", "run"); err == nil {
+		t.Error("expected error for invalid table name")
+	}
+}
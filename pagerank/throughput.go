@@ -0,0 +1,26 @@
+package pagerank
+
+import "time"
+
+type ThroughputResult struct {
+	ElapsedSeconds      float64
+	TotalEdgeIterations int64
+	EdgesPerSecond      float64
+}
+
+// BenchmarkEdgeThroughput times one Calculate run and reports how many
+// edges were processed per second, counting each edge once per iteration.
+func BenchmarkEdgeThroughput(backlinks map[string][]string, outlinksCount map[string]int, calc *Calculator) ThroughputResult {
+	start := time.Now()
+	calc.Calculate(backlinks, outlinksCount)
+	elapsed := time.Since(start).Seconds()
+
+	res := ThroughputResult{
+		ElapsedSeconds:      elapsed,
+		TotalEdgeIterations: int64(EdgeCount(backlinks)) * int64(calc.Iterations()),
+	}
+	if elapsed > 0 {
+		res.EdgesPerSecond = float64(res.TotalEdgeIterations) / elapsed
+	}
+	return res
+}
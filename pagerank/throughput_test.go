@@ -0,0 +1,16 @@
+package pagerank
+
+import "testing"
+
+func TestBenchmarkEdgeThroughput(t *testing.T) {
+	backlinks, outlinks := GeneratePowerLawGraph(1000, 4, 2)
+	calc := New().SetIterations(30)
+	res := BenchmarkEdgeThroughput(backlinks, outlinks, calc)
+
+	if want := int64(EdgeCount(backlinks)) * 30; res.TotalEdgeIterations != want {
+		t.Errorf("TotalEdgeIterations = %d, want %d", res.TotalEdgeIterations, want)
+	}
+	if res.ElapsedSeconds <= 0 || res.EdgesPerSecond <= 0 {
+		t.Errorf("expected positive timing, got %+v", res)
+	}
+}
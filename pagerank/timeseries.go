@@ -0,0 +1,96 @@
+package pagerank
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+type RankPoint struct {
+	Timestamp time.Time
+	Rank      float64
+}
+
+// TimeSeriesStore keeps every page's rank across crawl runs in memory. It
+// is safe for concurrent use.
+type TimeSeriesStore struct {
+	mu      sync.RWMutex
+	runs    map[string]bool
+	history map[string][]RankPoint
+}
+
+func NewTimeSeriesStore() *TimeSeriesStore {
+	return &TimeSeriesStore{
+		runs:    make(map[string]bool),
+		history: make(map[string][]RankPoint),
+	}
+}
+
+// AddRun records one run's results. Run IDs must be unique.
+func (ts *TimeSeriesStore) AddRun(runID string, timestamp time.Time, results []Result) error {
+	if runID == "" {
+		return errors.New("pagerank: empty run ID")
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.runs == nil {
+		ts.runs = make(map[string]bool)
+		ts.history = make(map[string][]RankPoint)
+	}
+	if ts.runs[runID] {
+		return fmt.Errorf("pagerank: run %q already recorded", runID)
+	}
+	ts.runs[runID] = true
+	for _, r := range results {
+		points := append(ts.history[r.URL], RankPoint{Timestamp: timestamp, Rank: r.Rank})
+		// Runs usually arrive in order; only re-sort when one doesn't.
+		if n := len(points); n > 1 && points[n-1].Timestamp.Before(points[n-2].Timestamp) {
+			sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+		}
+		ts.history[r.URL] = points
+	}
+	return nil
+}
+
+// GetHistory returns a copy of url's rank points, oldest first.
+func (ts *TimeSeriesStore) GetHistory(url string) ([]RankPoint, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	points, ok := ts.history[url]
+	if !ok {
+		return nil, fmt.Errorf("pagerank: no history for %q", url)
+	}
+	return append([]RankPoint(nil), points...), nil
+}
+
+// Trend returns the least-squares slope of url's last window rank points,
+// in rank per run. A window of zero or less uses the whole history. Fewer
+// than two points give a trend of 0.
+func (ts *TimeSeriesStore) Trend(url string, window int) float64 {
+	ts.mu.RLock()
+	points := ts.history[url]
+	if window > 0 && window < len(points) {
+		points = points[len(points)-window:]
+	}
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		ys[i] = p.Rank
+	}
+	ts.mu.RUnlock()
+
+	n := float64(len(ys))
+	if n < 2 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range ys {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
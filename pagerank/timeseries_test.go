@@ -0,0 +1,74 @@
+package pagerank
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesStoreTrend(t *testing.T) {
+	ts := NewTimeSeriesStore()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		results := []Result{
+			{URL: "rising", Rank: 0.1 + 0.05*float64(i)},
+			{URL: "falling", Rank: 0.5 - 0.1*float64(i)},
+			{URL: "flat", Rank: 0.2},
+		}
+		if err := ts.AddRun(fmt.Sprintf("run-%d", i), start.Add(time.Duration(i)*24*time.Hour), results); err != nil {
+			t.Fatalf("AddRun: %v", err)
+		}
+	}
+
+	if got := ts.Trend("rising", 5); math.Abs(got-0.05) > 1e-9 {
+		t.Errorf("rising trend = %v, want 0.05", got)
+	}
+	if got := ts.Trend("falling", 3); math.Abs(got+0.1) > 1e-9 {
+		t.Errorf("falling trend = %v, want -0.1", got)
+	}
+	if got := ts.Trend("flat", 0); math.Abs(got) > 1e-12 {
+		t.Errorf("flat trend = %v, want 0", got)
+	}
+	if got := ts.Trend("missing", 5); got != 0 {
+		t.Errorf("missing trend = %v, want 0", got)
+	}
+
+	history, err := ts.GetHistory("rising")
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	if len(history) != 5 || !history[4].Timestamp.Equal(start.Add(96*time.Hour)) {
+		t.Errorf("unexpected history %v", history)
+	}
+	if _, err := ts.GetHistory("missing"); err == nil {
+		t.Error("expected an error for an unknown URL")
+	}
+	if err := ts.AddRun("run-0", start, nil); err == nil {
+		t.Error("expected an error for a duplicate run ID")
+	}
+}
+
+func TestTimeSeriesStoreConcurrent(t *testing.T) {
+	ts := NewTimeSeriesStore()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ts.AddRun(fmt.Sprintf("run-%d", i), time.Unix(int64(i), 0), []Result{{URL: "a", Rank: float64(i)}})
+			ts.Trend("a", 4)
+		}(i)
+	}
+	wg.Wait()
+	history, _ := ts.GetHistory("a")
+	if len(history) != 8 {
+		t.Fatalf("expected 8 points, got %d", len(history))
+	}
+	for i := 1; i < len(history); i++ {
+		if history[i].Timestamp.Before(history[i-1].Timestamp) {
+			t.Fatalf("history out of order: %v", history)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package pagerank
+
+type TypedNode struct {
+	ID       string
+	NodeType string
+}
+
+type TypedEdge struct {
+	Source   TypedNode
+	Target   TypedNode
+	EdgeType string
+}
+
+// TypedGraph is a Graph whose nodes carry a type, so pages, authors,
+// topics and so on can share one link structure. Nodes with the same ID
+// but different types are distinct.
+type TypedGraph struct {
+	nodes     map[TypedNode]bool
+	edges     map[TypedEdge]bool
+	backlinks map[TypedNode][]TypedNode
+	outlinks  map[TypedNode]int
+}
+
+func NewTypedGraph() *TypedGraph {
+	return &TypedGraph{
+		nodes:     make(map[TypedNode]bool),
+		edges:     make(map[TypedEdge]bool),
+		backlinks: make(map[TypedNode][]TypedNode),
+		outlinks:  make(map[TypedNode]int),
+	}
+}
+
+func (g *TypedGraph) AddNode(n TypedNode) *TypedGraph {
+	if !g.nodes[n] {
+		g.nodes[n] = true
+		g.outlinks[n] = 0
+	}
+	return g
+}
+
+func (g *TypedGraph) AddEdge(e TypedEdge) *TypedGraph {
+	if g.edges[e] {
+		return g
+	}
+	g.AddNode(e.Source).AddNode(e.Target)
+	g.edges[e] = true
+	g.backlinks[e.Target] = append(g.backlinks[e.Target], e.Source)
+	g.outlinks[e.Source]++
+	return g
+}
+
+func (g *TypedGraph) NodeCount() int { return len(g.nodes) }
+func (g *TypedGraph) EdgeCount() int { return len(g.edges) }
+
+// Build returns copies of the typed backlinks and outlink counts.
+func (g *TypedGraph) Build() (map[TypedNode][]TypedNode, map[TypedNode]int) {
+	backlinks := make(map[TypedNode][]TypedNode, len(g.backlinks))
+	for target, sources := range g.backlinks {
+		backlinks[target] = append([]TypedNode(nil), sources...)
+	}
+	outlinks := make(map[TypedNode]int, len(g.outlinks))
+	for n, c := range g.outlinks {
+		outlinks[n] = c
+	}
+	return backlinks, outlinks
+}
+
+// CalculateTyped ranks all nodes of g together and returns the results
+// grouped by node type. Result URLs hold the node IDs.
+func (c *Calculator) CalculateTyped(g *TypedGraph) map[string][]Result {
+	key := func(n TypedNode) string { return n.NodeType + "\x00" + n.ID }
+	nodes := make(map[string]TypedNode, len(g.nodes))
+	backlinks := make(map[string][]string, len(g.backlinks))
+	outlinks := make(map[string]int, len(g.outlinks))
+	for n, count := range g.outlinks {
+		nodes[key(n)] = n
+		outlinks[key(n)] = count
+	}
+	for target, sources := range g.backlinks {
+		keys := make([]string, len(sources))
+		for i, src := range sources {
+			keys[i] = key(src)
+		}
+		backlinks[key(target)] = keys
+	}
+
+	out := make(map[string][]Result)
+	for _, r := range c.Calculate(backlinks, outlinks) {
+		n := nodes[r.URL]
+		out[n.NodeType] = append(out[n.NodeType], Result{URL: n.ID, Rank: r.Rank})
+	}
+	return out
+}
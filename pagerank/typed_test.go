@@ -0,0 +1,58 @@
+package pagerank
+
+import "testing"
+
+func TestCalculateTypedAuthorGraph(t *testing.T) {
+	wrote := map[string][]string{
+		"alice": {"post-1", "post-2", "post-3"},
+		"bob":   {"post-4"},
+	}
+	g := NewTypedGraph()
+	for author, posts := range wrote {
+		a := TypedNode{ID: author, NodeType: "author"}
+		for _, post := range posts {
+			p := TypedNode{ID: post, NodeType: "url"}
+			g.AddEdge(TypedEdge{Source: a, Target: p, EdgeType: "wrote"})
+			g.AddEdge(TypedEdge{Source: p, Target: a, EdgeType: "written_by"})
+		}
+	}
+	g.AddEdge(TypedEdge{
+		Source:   TypedNode{ID: "post-4", NodeType: "url"},
+		Target:   TypedNode{ID: "post-1", NodeType: "url"},
+		EdgeType: "cites",
+	})
+
+	if g.NodeCount() != 6 || g.EdgeCount() != 9 {
+		t.Fatalf("expected 6 nodes and 9 edges, got %d and %d", g.NodeCount(), g.EdgeCount())
+	}
+
+	byType := New().CalculateTyped(g)
+	if len(byType["author"]) != 2 || len(byType["url"]) != 4 {
+		t.Fatalf("expected 2 authors and 4 urls, got %v", byType)
+	}
+	if top := byType["author"][0]; top.URL != "alice" {
+		t.Errorf("alice wrote more posts and should outrank bob, got %v", byType["author"])
+	}
+	if top := byType["url"][0]; top.URL != "post-1" {
+		t.Errorf("post-1 is cited and should rank first among urls, got %v", byType["url"])
+	}
+
+	var sum float64
+	for _, rs := range byType {
+		for _, r := range rs {
+			sum += r.Rank
+		}
+	}
+	if sum < 0.99 || sum > 1.01 {
+		t.Errorf("ranks across types should sum to ~1, got %.4f", sum)
+	}
+}
+
+func TestTypedGraphSameIDDifferentType(t *testing.T) {
+	g := NewTypedGraph().
+		AddNode(TypedNode{ID: "go", NodeType: "topic"}).
+		AddNode(TypedNode{ID: "go", NodeType: "author"})
+	if g.NodeCount() != 2 {
+		t.Errorf("nodes with the same ID but different types should be distinct, got %d", g.NodeCount())
+	}
+}
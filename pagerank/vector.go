@@ -0,0 +1,47 @@
+package pagerank
+
+import (
+	"math"
+	"sort"
+)
+
+// RanksAsVector returns the ranks ordered by URL and scaled to unit L2
+// norm, for use as a feature vector. All-zero ranks stay zero.
+func RanksAsVector(results []Result) []float64 {
+	vec := ranksByURL(results)
+	var norm float64
+	for _, r := range vec {
+		norm += r * r
+	}
+	if norm = math.Sqrt(norm); norm > 0 {
+		for i := range vec {
+			vec[i] /= norm
+		}
+	}
+	return vec
+}
+
+// RanksAsLogVector returns, ordered by URL, the natural log of each rank's
+// share of the total, which spreads out the long tail of a power-law rank
+// distribution. Every value is at most 0; a zero rank gives -Inf.
+func RanksAsLogVector(results []Result) []float64 {
+	vec := ranksByURL(results)
+	var sum float64
+	for _, r := range vec {
+		sum += r
+	}
+	for i, r := range vec {
+		vec[i] = math.Log(r / sum)
+	}
+	return vec
+}
+
+func ranksByURL(results []Result) []float64 {
+	sorted := append([]Result(nil), results...)
+	sort.Sort(ByURLAsc(sorted))
+	vec := make([]float64, len(sorted))
+	for i, r := range sorted {
+		vec[i] = r.Rank
+	}
+	return vec
+}
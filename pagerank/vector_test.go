@@ -0,0 +1,38 @@
+package pagerank
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestRanksAsVector(t *testing.T) {
+	results := New().Calculate(GeneratePowerLawGraph(200, 3, 4))
+	byURL := append([]Result(nil), results...)
+	sort.Sort(ByURLAsc(byURL))
+
+	vec := RanksAsVector(results)
+	var norm float64
+	for i, v := range vec {
+		norm += v * v
+		if want := byURL[i].Rank / byURL[0].Rank; math.Abs(v/vec[0]-want) > 1e-9 {
+			t.Fatalf("entry %d (%s) is out of proportion: %v, want %v", i, byURL[i].URL, v/vec[0], want)
+		}
+	}
+	if math.Abs(math.Sqrt(norm)-1) > 1e-10 {
+		t.Errorf("L2 norm %v, want 1", math.Sqrt(norm))
+	}
+
+	logs := RanksAsLogVector(results)
+	if len(logs) != len(results) {
+		t.Fatalf("got %d values for %d results", len(logs), len(results))
+	}
+	for i, v := range logs {
+		if !(v < 0) {
+			t.Errorf("%s: log value %v is not negative", byURL[i].URL, v)
+		}
+	}
+	if got := RanksAsVector(nil); len(got) != 0 {
+		t.Errorf("empty results gave %v", got)
+	}
+}
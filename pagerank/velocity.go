@@ -0,0 +1,57 @@
+package pagerank
+
+import (
+	"context"
+	"time"
+)
+
+// ComputeLinkVelocity returns, for every page in after, how many inlinks
+// it gained since before, per day of elapsed. Lost inlinks are not
+// subtracted. A non-positive elapsed returns an empty map.
+func ComputeLinkVelocity(before, after map[string][]string, elapsed time.Duration) map[string]float64 {
+	velocity := make(map[string]float64, len(after))
+	if elapsed <= 0 {
+		return velocity
+	}
+	days := elapsed.Hours() / 24
+	for url, sources := range after {
+		old := make(map[string]bool, len(before[url]))
+		for _, src := range before[url] {
+			old[src] = true
+		}
+		gained := 0
+		for _, src := range sources {
+			if !old[src] {
+				gained++
+				old[src] = true
+			}
+		}
+		velocity[url] = float64(gained) / days
+	}
+	return velocity
+}
+
+// VelocityWeightedPageRank ranks the graph with default settings, scaling
+// each link's contribution by 1 + vweight*velocity[source] so links from
+// fast-growing pages count for more. Negative factors are treated as 0.
+// The ranks are not renormalized.
+func VelocityWeightedPageRank(backlinks map[string][]string, outlinksCount map[string]int, velocity map[string]float64, vweight float64) []Result {
+	g := buildLinkIndex(backlinks, outlinksCount)
+	if len(g.urls) == 0 {
+		return []Result{}
+	}
+	// Every link out of a page gets the same factor, so it folds into the
+	// page's divisor.
+	for i, url := range g.urls {
+		factor := 1 + vweight*velocity[url]
+		if factor < 0 {
+			factor = 0
+		}
+		g.out[i] /= factor
+	}
+	results, err := New().rankResults(context.Background(), g, 1)
+	if err != nil {
+		return []Result{}
+	}
+	return results
+}
@@ -0,0 +1,51 @@
+package pagerank
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeLinkVelocity(t *testing.T) {
+	before := map[string][]string{
+		"hot":  {"a", "b"},
+		"cold": {"a"},
+	}
+	after := map[string][]string{
+		"hot":  {"a", "b", "c", "d"},
+		"cold": {"a"},
+	}
+	velocity := ComputeLinkVelocity(before, after, 24*time.Hour)
+	if velocity["hot"] != 2 {
+		t.Errorf("hot velocity = %v, want 2 inlinks/day", velocity["hot"])
+	}
+	if velocity["cold"] != 0 {
+		t.Errorf("cold velocity = %v, want 0", velocity["cold"])
+	}
+	if v := ComputeLinkVelocity(before, after, 12*time.Hour); v["hot"] != 4 {
+		t.Errorf("half-day velocity = %v, want 4", v["hot"])
+	}
+	if v := ComputeLinkVelocity(before, after, 0); len(v) != 0 {
+		t.Errorf("zero elapsed should give no velocities, got %v", v)
+	}
+}
+
+func TestVelocityWeightedPageRank(t *testing.T) {
+	backlinks, outlinks := sampleGraph()
+	standard := ScoreMap(New().Calculate(backlinks, outlinks))
+
+	if got := ScoreMap(VelocityWeightedPageRank(backlinks, outlinks, nil, 1)); len(got) != len(standard) {
+		t.Fatalf("expected %d results, got %d", len(standard), len(got))
+	} else {
+		for url, rank := range standard {
+			if got[url] != rank {
+				t.Errorf("zero velocity changed %s: %v -> %v", url, rank, got[url])
+			}
+		}
+	}
+
+	// page-d only links to page-c, so boosting page-d lifts page-c.
+	weighted := ScoreMap(VelocityWeightedPageRank(backlinks, outlinks, map[string]float64{"page-d": 2}, 0.5))
+	if weighted["page-c"] <= standard["page-c"] {
+		t.Errorf("page-c rank %v not above standard %v", weighted["page-c"], standard["page-c"])
+	}
+}
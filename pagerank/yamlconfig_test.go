@@ -0,0 +1,67 @@
+package pagerank
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const sampleYAMLGraph = `
+damping: 0.9
+iterations: 30
+personalization:
+  a: 0.5
+nodes: [d]
+edges:
+  - {source: a, target: b}
+  - {source: b, target: c}
+  - {source: c, target: a}
+  - {source: a, target: c}
+  - {source: a, target: b}
+`
+
+func TestLoadGraphConfigFromYAML(t *testing.T) {
+	cfg, err := LoadGraphConfigFromYAML(strings.NewReader(sampleYAMLGraph))
+	if err != nil {
+		t.Fatalf("LoadGraphConfigFromYAML: %v", err)
+	}
+	wantOut := map[string]int{"a": 2, "b": 1, "c": 1, "d": 0}
+	if fmt.Sprint(cfg.OutlinksCount) != fmt.Sprint(wantOut) {
+		t.Errorf("outlinks = %v, want %v", cfg.OutlinksCount, wantOut)
+	}
+	if fmt.Sprint(cfg.Backlinks["c"]) != "[b a]" {
+		t.Errorf("backlinks[c] = %v", cfg.Backlinks["c"])
+	}
+
+	calc := cfg.Apply(New())
+	if calc.Damping() != 0.9 || calc.Iterations() != 30 {
+		t.Errorf("got damping=%v iterations=%d", calc.Damping(), calc.Iterations())
+	}
+	if calc.restart["a"] != 0.5 {
+		t.Errorf("personalization not applied: %v", calc.restart)
+	}
+
+	backlinks, outlinks, err := LoadGraphFromYAML(strings.NewReader("edges:\n  - {source: x, target: y}\n"))
+	if err != nil {
+		t.Fatalf("LoadGraphFromYAML: %v", err)
+	}
+	if len(backlinks["y"]) != 1 || outlinks["x"] != 1 {
+		t.Errorf("unexpected graph %v %v", backlinks, outlinks)
+	}
+	calc = (&GraphConfig{}).Apply(New())
+	if calc.Damping() != 0.85 || calc.Iterations() != 50 {
+		t.Errorf("empty config changed defaults: %v", calc)
+	}
+}
+
+func TestLoadGraphConfigFromYAMLErrors(t *testing.T) {
+	for _, doc := range []string{
+		"edges:\n  - {source: a}\n",
+		"edge:\n  - {source: a, target: b}\n",
+		"edges: [",
+	} {
+		if _, err := LoadGraphConfigFromYAML(strings.NewReader(doc)); err == nil {
+			t.Errorf("expected an error for %q", doc)
+		}
+	}
+}